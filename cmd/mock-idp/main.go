@@ -0,0 +1,278 @@
+// Command mock-idp is a tiny OpenID Connect provider for trying single sign-on
+// locally. It asks for an email and name instead of a password and signs ID
+// tokens with a key generated at startup. Never use it outside development.
+//
+//	go run ./cmd/mock-idp
+//	OIDC_ISSUER=http://localhost:9400 OIDC_CLIENT_ID=managify go run .
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"html/template"
+	"log"
+	"math/big"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const keyID = "mock-idp"
+
+type grant struct {
+	clientID      string
+	redirectURI   string
+	challenge     string
+	nonce         string
+	email         string
+	name          string
+	emailVerified bool
+	expiresAt     time.Time
+}
+
+type server struct {
+	issuer   string
+	clientID string
+	secret   string
+	key      *rsa.PrivateKey
+
+	mu     sync.Mutex
+	codes  map[string]grant
+	tokens map[string]grant
+}
+
+var loginPage = template.Must(template.New("login").Parse(`<!doctype html>
+<html>
+<body style="font-family:sans-serif;max-width:360px;margin:60px auto">
+<h2>Mock identity provider</h2>
+<form method="get">
+{{range $k, $v := .Params}}<input type="hidden" name="{{$k}}" value="{{index $v 0}}">
+{{end}}<p><label>Email<br><input name="email" type="email" required></label></p>
+<p><label>Name<br><input name="name"></label></p>
+<p><label><input name="email_verified" type="checkbox" value="true" checked> Email verified</label></p>
+<button type="submit">Log in</button>
+</form>
+</body>
+</html>`))
+
+func env(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func random() string {
+	b := make([]byte, 24)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func tokenError(w http.ResponseWriter, code, description string) {
+	writeJSON(w, http.StatusBadRequest, map[string]string{"error": code, "error_description": description})
+}
+
+func (s *server) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                s.issuer,
+		"authorization_endpoint":                s.issuer + "/authorize",
+		"token_endpoint":                        s.issuer + "/token",
+		"userinfo_endpoint":                     s.issuer + "/userinfo",
+		"jwks_uri":                              s.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{"RS256"},
+		"code_challenge_methods_supported":      []string{"S256"},
+	})
+}
+
+func (s *server) jwks(w http.ResponseWriter, r *http.Request) {
+	pub := s.key.PublicKey
+	writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+		"kty": "RSA",
+		"use": "sig",
+		"alg": "RS256",
+		"kid": keyID,
+		"n":   base64.RawURLEncoding.EncodeToString(pub.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes()),
+	}}})
+}
+
+// authorize shows the login form and redirects back with a code once it is
+// submitted.
+func (s *server) authorize(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("client_id") != s.clientID || q.Get("response_type") != "code" || q.Get("redirect_uri") == "" {
+		http.Error(w, "invalid authorization request", http.StatusBadRequest)
+		return
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		http.Error(w, "PKCE with S256 is required", http.StatusBadRequest)
+		return
+	}
+
+	if q.Get("email") == "" {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_ = loginPage.Execute(w, map[string]any{"Params": q})
+		return
+	}
+
+	code := random()
+	s.mu.Lock()
+	s.codes[code] = grant{
+		clientID:      s.clientID,
+		redirectURI:   q.Get("redirect_uri"),
+		challenge:     q.Get("code_challenge"),
+		nonce:         q.Get("nonce"),
+		email:         q.Get("email"),
+		name:          q.Get("name"),
+		emailVerified: q.Get("email_verified") == "true",
+		expiresAt:     time.Now().Add(time.Minute),
+	}
+	s.mu.Unlock()
+
+	redirect, err := url.Parse(q.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	params := redirect.Query()
+	params.Set("code", code)
+	params.Set("state", q.Get("state"))
+	redirect.RawQuery = params.Encode()
+	http.Redirect(w, r, redirect.String(), http.StatusFound)
+}
+
+func (s *server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.ParseForm() != nil {
+		tokenError(w, "invalid_request", "expected a form POST")
+		return
+	}
+	clientID, secret, ok := r.BasicAuth()
+	if !ok {
+		clientID, secret = r.PostForm.Get("client_id"), r.PostForm.Get("client_secret")
+	}
+	if clientID != s.clientID || (s.secret != "" && secret != s.secret) {
+		tokenError(w, "invalid_client", "unknown client or wrong secret")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type", "only authorization_code is supported")
+		return
+	}
+
+	s.mu.Lock()
+	g, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+	if !ok || time.Now().After(g.expiresAt) || g.redirectURI != r.PostForm.Get("redirect_uri") {
+		tokenError(w, "invalid_grant", "code is invalid, expired or was issued for another redirect_uri")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != g.challenge {
+		tokenError(w, "invalid_grant", "code_verifier does not match the code_challenge")
+		return
+	}
+
+	now := time.Now()
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":            s.issuer,
+		"sub":            subject(g.email),
+		"aud":            s.clientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(5 * time.Minute).Unix(),
+		"nonce":          g.nonce,
+		"email":          g.email,
+		"email_verified": g.emailVerified,
+		"name":           g.name,
+	})
+	idToken.Header["kid"] = keyID
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		tokenError(w, "server_error", err.Error())
+		return
+	}
+
+	accessToken := random()
+	s.mu.Lock()
+	s.tokens[accessToken] = g
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": accessToken,
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+func (s *server) userinfo(w http.ResponseWriter, r *http.Request) {
+	auth := r.Header.Get("Authorization")
+	if len(auth) < 7 || auth[:7] != "Bearer " {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	s.mu.Lock()
+	g, ok := s.tokens[auth[7:]]
+	s.mu.Unlock()
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"sub":            subject(g.email),
+		"email":          g.email,
+		"email_verified": g.emailVerified,
+		"name":           g.name,
+	})
+}
+
+// subject derives a stable subject from the email, so logging in with the
+// same email after a restart maps to the same identity.
+func subject(email string) string {
+	sum := sha256.Sum256([]byte(email))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func main() {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	addr := env("MOCK_IDP_ADDR", ":9400")
+	s := &server{
+		issuer:   env("MOCK_IDP_ISSUER", "http://localhost:9400"),
+		clientID: env("MOCK_IDP_CLIENT_ID", "managify"),
+		secret:   os.Getenv("MOCK_IDP_CLIENT_SECRET"),
+		key:      key,
+		codes:    map[string]grant{},
+		tokens:   map[string]grant{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", s.discovery)
+	mux.HandleFunc("/jwks", s.jwks)
+	mux.HandleFunc("/authorize", s.authorize)
+	mux.HandleFunc("/token", s.token)
+	mux.HandleFunc("/userinfo", s.userinfo)
+
+	log.Printf("mock identity provider %s listening on %s", s.issuer, addr)
+	log.Fatal(http.ListenAndServe(addr, mux))
+}
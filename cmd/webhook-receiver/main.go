@@ -0,0 +1,66 @@
+// Command webhook-receiver prints the webhook deliveries it receives and
+// checks their signatures, for trying webhooks locally. Set
+// WEBHOOK_RECEIVER_FAIL=true to answer with 500 and watch the retries.
+//
+//	WEBHOOK_RECEIVER_SECRET=whsec_... go run ./cmd/webhook-receiver
+//	WEBHOOK_ALLOW_PRIVATE_NETWORKS=true go run .
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"managify/internal/webhook"
+	"net/http"
+	"os"
+	"time"
+)
+
+func env(name, def string) string {
+	if v := os.Getenv(name); v != "" {
+		return v
+	}
+	return def
+}
+
+func main() {
+	addr := env("WEBHOOK_RECEIVER_ADDR", ":9500")
+	secret := os.Getenv("WEBHOOK_RECEIVER_SECRET")
+	fail := os.Getenv("WEBHOOK_RECEIVER_FAIL") == "true"
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		verified := "not checked"
+		if secret != "" {
+			verified = "invalid"
+			if webhook.Verify(secret, r.Header.Get(webhook.HeaderTimestamp), r.Header.Get(webhook.HeaderSignature), body, 5*time.Minute) {
+				verified = "valid"
+			}
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("%s delivery %s, signature %s\n%s",
+			r.Header.Get(webhook.HeaderEvent), r.Header.Get(webhook.HeaderDelivery), verified, pretty.String())
+
+		switch {
+		case verified == "invalid":
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+		case fail:
+			http.Error(w, "failing on purpose", http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	})
+
+	log.Printf("webhook receiver listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
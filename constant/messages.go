@@ -18,6 +18,7 @@ const (
 	ErrConflict        = "Resource conflict"
 	ErrValidation      = "Validation failed"
 	ErrTooManyRequests = "Too many requests"
+	ErrEmailUnverified = "Email verification required"
 )
 
 // Server error messages (5xx)
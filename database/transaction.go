@@ -0,0 +1,36 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WithTransaction runs fn inside a multi-document transaction. Standalone
+// MongoDB servers do not support transactions, in which case fn is run once
+// without one so local development keeps working.
+func WithTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	session, err := DB.Client().StartSession()
+	if err != nil {
+		return fn(ctx)
+	}
+	defer session.EndSession(ctx)
+
+	_, err = session.WithTransaction(ctx, func(sc mongo.SessionContext) (interface{}, error) {
+		return nil, fn(sc)
+	})
+	if err != nil && transactionsUnsupported(err) {
+		return fn(ctx)
+	}
+	return err
+}
+
+func transactionsUnsupported(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		// 20: IllegalOperation, returned by standalone servers for transactions.
+		return cmdErr.Code == 20
+	}
+	return false
+}
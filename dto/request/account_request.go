@@ -0,0 +1,29 @@
+package request
+
+type ProfileUpdateRequest struct {
+	FullName string `json:"full_name"`
+}
+
+type PasswordChangeRequest struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+type EmailChangeRequest struct {
+	Email           string `json:"email"`
+	CurrentPassword string `json:"current_password"`
+}
+
+// ProjectTransferRequest hands an owned project over to one of its members
+// when the owner deletes their account.
+type ProjectTransferRequest struct {
+	ProjectID  string `json:"project_id"`
+	NewOwnerID string `json:"new_owner_id"`
+}
+
+// AccountDeleteRequest deletes the caller's account. Owned projects listed in
+// Transfers are handed over, every other owned project is deleted.
+type AccountDeleteRequest struct {
+	Password  string                   `json:"password"`
+	Transfers []ProjectTransferRequest `json:"transfers"`
+}
@@ -0,0 +1,8 @@
+package request
+
+type APIKeyCreateRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// ExpiresInDays defaults to 90 and may be at most 365.
+	ExpiresInDays int `json:"expires_in_days"`
+}
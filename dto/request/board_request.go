@@ -0,0 +1,19 @@
+package request
+
+type MoveIssueRequest struct {
+	StatusID string `json:"status_id"`
+	// Position is the zero-based index in the target column; omitted means
+	// the bottom of the column.
+	Position *int `json:"position"`
+	// Resolution is stored on the issue; workflows can require it when
+	// entering a status.
+	Resolution string `json:"resolution"`
+}
+
+type IssueStatusRequest struct {
+	Resolution string `json:"resolution"`
+}
+
+type ReorderColumnsRequest struct {
+	StatusIDs []string `json:"status_ids"`
+}
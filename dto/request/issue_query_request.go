@@ -0,0 +1,18 @@
+package request
+
+// IssueQueryRequest holds the filters of the project issue query endpoint.
+// List values accept both repeated and comma separated parameters.
+type IssueQueryRequest struct {
+	Priority []string `query:"priority"`
+	Status   []string `query:"status"`
+	StatusID string   `query:"status_id"`
+	Tags     []string `query:"tags"`
+	DueFrom  string   `query:"due_from"`
+	DueTo    string   `query:"due_to"`
+	Assignee string   `query:"assignee"`
+	Text     string   `query:"q"`
+	Sort     string   `query:"sort"`
+	Order    string   `query:"order"`
+	Limit    int      `query:"limit"`
+	Cursor   string   `query:"cursor"`
+}
@@ -0,0 +1,13 @@
+package request
+
+// IssueUpdateRequest carries a partial issue update; nil fields are left untouched.
+// Status is only accepted to reject it: issues change status through the board
+// move endpoint so the workflow is enforced.
+type IssueUpdateRequest struct {
+	Title       *string   `json:"title"`
+	Description *string   `json:"description"`
+	Priority    *string   `json:"priority"`
+	Status      *string   `json:"status"`
+	DueDate     *string   `json:"due_date"`
+	Tags        *[]string `json:"tags"`
+}
@@ -0,0 +1,5 @@
+package request
+
+type MemberRoleRequest struct {
+	Role string `json:"role"`
+}
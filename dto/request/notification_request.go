@@ -0,0 +1,8 @@
+package request
+
+type NotificationPreferencesRequest struct {
+	// EmailMuted lists the events that are not emailed.
+	EmailMuted []string `json:"email_muted"`
+	// Digest is instant, hourly or daily.
+	Digest string `json:"digest"`
+}
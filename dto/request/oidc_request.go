@@ -0,0 +1,8 @@
+package request
+
+// OIDCCallbackRequest carries the query parameters the identity provider
+// appended to the redirect URL.
+type OIDCCallbackRequest struct {
+	Code  string `json:"code"`
+	State string `json:"state"`
+}
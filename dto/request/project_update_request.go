@@ -0,0 +1,13 @@
+package request
+
+// ProjectUpdateRequest carries a partial project update; nil fields are left untouched.
+type ProjectUpdateRequest struct {
+	Name        *string   `json:"name"`
+	Description *string   `json:"description"`
+	Category    *string   `json:"category"`
+	Tags        *[]string `json:"tags"`
+}
+
+type ProjectStatusRequest struct {
+	Status string `json:"status"`
+}
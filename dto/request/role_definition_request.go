@@ -0,0 +1,6 @@
+package request
+
+type RoleDefinitionRequest struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
@@ -0,0 +1,16 @@
+package request
+
+type TwoFactorLoginRequest struct {
+	ChallengeToken string `json:"challenge_token"`
+	Code           string `json:"code"`
+}
+
+// TwoFactorCodeRequest carries an authenticator code or a recovery code.
+type TwoFactorCodeRequest struct {
+	Code string `json:"code"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
@@ -0,0 +1,16 @@
+package request
+
+type WebhookCreateRequest struct {
+	URL string `json:"url"`
+	// Secret signs the deliveries; a random one is generated when empty.
+	Secret string `json:"secret"`
+	// Events filters the delivered events; empty means all events.
+	Events []string `json:"events"`
+}
+
+type WebhookUpdateRequest struct {
+	URL    *string   `json:"url"`
+	Secret *string   `json:"secret"`
+	Events *[]string `json:"events"`
+	Active *bool     `json:"active"`
+}
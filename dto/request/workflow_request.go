@@ -0,0 +1,12 @@
+package request
+
+type WorkflowTransitionRequest struct {
+	FromStatusID   string   `json:"from_status_id"`
+	ToStatusID     string   `json:"to_status_id"`
+	RequiredFields []string `json:"required_fields"`
+	AllowedRoles   []string `json:"allowed_roles"`
+}
+
+type WorkflowRequest struct {
+	Transitions []WorkflowTransitionRequest `json:"transitions"`
+}
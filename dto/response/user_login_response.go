@@ -1,7 +1,13 @@
 package response
 
+// UserLoginResponse carries either the session tokens or, for users with
+// two-factor authentication, the challenge token of the second login step.
 type UserLoginResponse struct {
-	FullName string `json:"full_name"`
-	Email    string `json:"email"`
-	Token    string `json:"token"`
+	FullName          string `json:"full_name"`
+	Email             string `json:"email"`
+	Token             string `json:"token,omitempty"`
+	RefreshToken      string `json:"refresh_token,omitempty"`
+	IsVerified        bool   `json:"is_verified"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	ChallengeToken    string `json:"challenge_token,omitempty"`
 }
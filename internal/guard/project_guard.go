@@ -0,0 +1,143 @@
+package guard
+
+import (
+	"fmt"
+	"managify/constant"
+	"managify/internal/service"
+	"managify/models"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectResolver finds the project a request operates on.
+type ProjectResolver func(c *fiber.Ctx) (primitive.ObjectID, error)
+
+// Require authorizes the caller for perm on the project returned by resolve.
+// Admins pass every check. On success the project ID and the caller's role are
+// stored in c.Locals("project_id") and c.Locals("project_role").
+func Require(perm models.Permission, resolve ProjectResolver) fiber.Handler {
+	return require(perm, resolve, service.GetRoleService().GetUserAccess)
+}
+
+// RequireIncludingDeleted is Require for routes that also act on projects in
+// the trash, such as deleting and restoring them.
+func RequireIncludingDeleted(perm models.Permission, resolve ProjectResolver) fiber.Handler {
+	return require(perm, resolve, service.GetRoleService().GetUserAccessIncludingDeleted)
+}
+
+func require(perm models.Permission, resolve ProjectResolver, getAccess func(userID, projectID primitive.ObjectID) (*service.ProjectAccess, error)) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		user, ok := utils.GetUserLocal(c)
+		if !ok {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": constant.ErrUnauthorized,
+			})
+		}
+
+		projectID, err := resolve(c)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+				"error":   err.Error(),
+			})
+		}
+		c.Locals("project_id", projectID)
+
+		if user.IsAdmin {
+			return c.Next()
+		}
+
+		access, err := getAccess(user.ID, projectID)
+		if err != nil {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": constant.ErrForbidden,
+				"error":   err.Error(),
+			})
+		}
+
+		if !access.Allows(perm) {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+				"message": constant.ErrForbidden,
+				"error":   fmt.Sprintf("missing permission %s", perm),
+			})
+		}
+		c.Locals("project_role", access.Role)
+
+		return c.Next()
+	}
+}
+
+// FromParam reads the project ID from a path parameter.
+func FromParam(name string) ProjectResolver {
+	return func(c *fiber.Ctx) (primitive.ObjectID, error) {
+		return parseID(c.Params(name), name)
+	}
+}
+
+// FromBody reads the project ID from the "project_id" field of a JSON body.
+func FromBody() ProjectResolver {
+	return func(c *fiber.Ctx) (primitive.ObjectID, error) {
+		var body struct {
+			ProjectID string `json:"project_id"`
+		}
+		if err := c.BodyParser(&body); err != nil {
+			return primitive.NilObjectID, fmt.Errorf("invalid request body")
+		}
+		return parseID(body.ProjectID, "project_id")
+	}
+}
+
+// FromIssueParam resolves the project of the issue named by a path parameter.
+func FromIssueParam(name string) ProjectResolver {
+	return func(c *fiber.Ctx) (primitive.ObjectID, error) {
+		issueID, err := parseID(c.Params(name), name)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		issue, err := service.GetIssueService().GetIssueById(issueID)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		return issue.ProjectID, nil
+	}
+}
+
+// FromCommentParam resolves the project of the comment named by a path parameter.
+func FromCommentParam(name string) ProjectResolver {
+	return func(c *fiber.Ctx) (primitive.ObjectID, error) {
+		commentID, err := parseID(c.Params(name), name)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		comment, err := service.GetCommentService().GetCommentById(commentID)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		return comment.ProjectID, nil
+	}
+}
+
+// FromStatusParam resolves the project of the status named by a path parameter.
+func FromStatusParam(name string) ProjectResolver {
+	return func(c *fiber.Ctx) (primitive.ObjectID, error) {
+		statusID, err := parseID(c.Params(name), name)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		status, err := service.GetStatusService().GetStatusById(statusID)
+		if err != nil {
+			return primitive.NilObjectID, err
+		}
+		return status.ProjectID, nil
+	}
+}
+
+func parseID(hex, name string) (primitive.ObjectID, error) {
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		return primitive.NilObjectID, fmt.Errorf("invalid %s", name)
+	}
+	return id, nil
+}
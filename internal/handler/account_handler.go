@@ -0,0 +1,221 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Get own profile
+// @Description Returns the profile of the authenticated user.
+// @Tags Account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me [get]
+func GetProfileHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	profile, err := service.GetUserService().GetUserById(user.ID.Hex())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    profile,
+	})
+}
+
+// @Summary Update own profile
+// @Description Changes the full name of the authenticated user.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.ProfileUpdateRequest true "Profile fields"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me [put]
+func UpdateProfileHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.ProfileUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	profile, err := service.GetUserService().UpdateProfile(user.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    profile,
+	})
+}
+
+// @Summary Change own password
+// @Description Changes the password after checking the current one. Other sessions are logged out and a new token pair is returned.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.PasswordChangeRequest true "Current and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/password [put]
+func ChangePasswordHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.PasswordChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	pair, err := service.GetUserService().ChangePassword(user.ID, &req, sessionInfo(c))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    pair,
+	})
+}
+
+// @Summary Change own email
+// @Description Sends a confirmation link to the new address. The email changes once the link is used.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.EmailChangeRequest true "New email and current password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/email [post]
+func RequestEmailChangeHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.EmailChangeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetUserService().RequestEmailChange(user.ID, &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "A confirmation link has been sent to the new email",
+	})
+}
+
+// @Summary Confirm email change
+// @Description Switches the account to the new email using the emailed token.
+// @Tags Account
+// @Produce json
+// @Param token query string true "Email change token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/confirm-email [get]
+func ConfirmEmailChangeHandler(c *fiber.Ctx) error {
+	token := c.Query("token")
+	if token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "Token missing"})
+	}
+
+	user, err := service.GetUserService().ConfirmEmailChange(token)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"message": "Email changed", "user": user.Email})
+}
+
+// @Summary Delete own account
+// @Description Deletes the authenticated user. Owned projects listed in transfers are handed over to a team member, the others are deleted permanently.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.AccountDeleteRequest true "Password and project transfers"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me [delete]
+func DeleteAccountHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.AccountDeleteRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetUserService().DeleteAccount(user.ID, &req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
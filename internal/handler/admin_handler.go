@@ -3,8 +3,11 @@ package handler
 import (
 	"managify/constant"
 	"managify/internal/service"
+	"managify/models"
+	"managify/utils"
 
 	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func GetUsersHandler(c *fiber.Ctx) error {
@@ -95,3 +98,140 @@ func GetRolesHandler(c *fiber.Ctx) error {
 		"data":    roles,
 	})
 }
+
+func CreateProjectTemplateHandler(c *fiber.Ctx) error {
+	var template models.ProjectTemplate
+	if err := c.BodyParser(&template); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	res, err := service.GetProjectTemplateService().CreateTemplate(&template, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": constant.SuccessCreated,
+		"data":    res,
+	})
+}
+
+func DeleteProjectTemplateHandler(c *fiber.Ctx) error {
+	templateID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetProjectTemplateService().DeleteTemplate(templateID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
+
+func UnlockUserHandler(c *fiber.Ctx) error {
+	admin, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	user, err := service.GetUserService().GetUserById(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	if err := service.GetLoginGuard().Unlock(admin.ID, user); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+	})
+}
+
+func GetAuditLogsHandler(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	entries, err := service.GetAuditService().GetAuditLogs(c.Query("action"), int64(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    entries,
+	})
+}
+
+func GetEmailsHandler(c *fiber.Ctx) error {
+	limit := c.QueryInt("limit", 100)
+	if limit <= 0 || limit > 500 {
+		limit = 100
+	}
+
+	emails, err := service.GetMailService().GetEmails(c.Query("status"), int64(limit))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    emails,
+	})
+}
+
+func RetryEmailHandler(c *fiber.Ctx) error {
+	emailID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetMailService().RetryEmail(emailID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": constant.ErrNotFound,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+	})
+}
@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Create an API key
+// @Description Mints a named personal API key with read, write or admin scope. The key is returned only once; send it as X-API-Key or as a Bearer token.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.APIKeyCreateRequest true "Name, scopes and lifetime"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/api-keys [post]
+func CreateAPIKeyHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.APIKeyCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	key, plain, err := service.GetAPIKeyService().CreateKey(user, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": constant.SuccessCreated,
+		"data":    fiber.Map{"key": plain, "api_key": key},
+	})
+}
+
+// @Summary List API keys
+// @Description Returns the API keys of the authenticated user with their last use, including expired and revoked ones.
+// @Tags Account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/api-keys [get]
+func GetAPIKeysHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	keys, err := service.GetAPIKeyService().GetKeys(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    keys,
+	})
+}
+
+// @Summary Revoke an API key
+// @Description Revokes one of the API keys of the authenticated user. Requests with it are rejected immediately.
+// @Tags Account
+// @Produce json
+// @Param keyID path string true "API key ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/api-keys/{keyID} [delete]
+func RevokeAPIKeyHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	keyID, err := primitive.ObjectIDFromHex(c.Params("keyID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetAPIKeyService().RevokeKey(user.ID, keyID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": constant.ErrNotFound,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/internal/service"
+	"managify/models"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Assign an issue
+// @Description Assigns an unassigned issue to a member of the project team.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param userID path string true "Assignee user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/assign/{issueID}/{userID} [put]
+func AssignIssueHandler(c *fiber.Ctx) error {
+	return changeAssignee(c, service.GetIssueService().AssignIssue)
+}
+
+// @Summary Reassign an issue
+// @Description Moves an issue to another member of the project team.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param userID path string true "New assignee user ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/reassign/{issueID}/{userID} [put]
+func ReassignIssueHandler(c *fiber.Ctx) error {
+	return changeAssignee(c, service.GetIssueService().ReassignIssue)
+}
+
+func changeAssignee(c *fiber.Ctx, assign func(issueID, assigneeID, userID primitive.ObjectID) (*models.Issue, error)) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	assigneeID, err := primitive.ObjectIDFromHex(c.Params("userID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issue, err := assign(issueID, assigneeID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    issue,
+	})
+}
+
+// @Summary Unassign an issue
+// @Description Removes the current assignee from an issue.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/unassign/{issueID} [put]
+func UnassignIssueHandler(c *fiber.Ctx) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issue, err := service.GetIssueService().UnassignIssue(issueID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    issue,
+	})
+}
+
+// @Summary Get my assigned issues
+// @Description Retrieves every issue assigned to the authenticated user, ordered by due date.
+// @Tags Issues
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/assigned [get]
+func GetAssignedIssuesHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issues, err := service.GetIssueService().GetAssignedIssues(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    issues,
+	})
+}
+
+// @Summary Get project workload
+// @Description Retrieves the number of assigned issues per project member, grouped by priority and status.
+// @Tags Issues
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/workload/{projectID} [get]
+func GetWorkloadHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	workload, err := service.GetIssueService().GetWorkload(projectID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    workload,
+	})
+}
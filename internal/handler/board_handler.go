@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Get project board
+// @Description Returns the statuses of a project in column order, each with its issues in card order.
+// @Tags Board
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /board/{projectID} [get]
+func GetBoardHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	board, err := service.GetBoardService().GetBoard(projectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    board,
+	})
+}
+
+// @Summary Move an issue on the board
+// @Description Moves an issue to a position within its column or into another column, subject to the project workflow. Omitting position puts it at the bottom.
+// @Tags Board
+// @Accept json
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param move body request.MoveIssueRequest true "Target column and position"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /board/move-issue/{issueID} [put]
+func MoveIssueHandler(c *fiber.Ctx) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.MoveIssueRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	statusID, err := primitive.ObjectIDFromHex(req.StatusID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   "invalid status_id",
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issue, err := service.GetBoardService().MoveIssue(issueID, user.ID, service.IssueMove{
+		StatusID:   statusID,
+		Position:   req.Position,
+		Resolution: req.Resolution,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    issue,
+	})
+}
+
+// @Summary Reorder board columns
+// @Description Sets the column order of a project. status_ids must list every status of the project exactly once.
+// @Tags Board
+// @Accept json
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param order body request.ReorderColumnsRequest true "Status IDs in the new order"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /board/reorder-columns/{projectID} [put]
+func ReorderColumnsHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.ReorderColumnsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	statusIDs := make([]primitive.ObjectID, 0, len(req.StatusIDs))
+	for _, hex := range req.StatusIDs {
+		id, err := primitive.ObjectIDFromHex(hex)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+				"error":   "invalid status id " + hex,
+			})
+		}
+		statusIDs = append(statusIDs, id)
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	board, err := service.GetBoardService().ReorderColumns(projectID, statusIDs, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    board,
+	})
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Add a comment to an issue
+// @Description Posts a new top-level comment on an issue. Only project members can comment.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param comment body request.CommentRequest true "Comment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/comment/{issueID} [post]
+func CreateCommentHandler(c *fiber.Ctx) error {
+	return createComment(c, primitive.NilObjectID)
+}
+
+// @Summary Reply to a comment
+// @Description Posts a reply in the thread of an existing comment.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param commentID path string true "Parent comment ID"
+// @Param comment body request.CommentRequest true "Comment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/comment/{issueID}/reply/{commentID} [post]
+func ReplyCommentHandler(c *fiber.Ctx) error {
+	parentID, err := primitive.ObjectIDFromHex(c.Params("commentID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+	return createComment(c, parentID)
+}
+
+func createComment(c *fiber.Ctx, parentID primitive.ObjectID) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	comment, err := service.GetCommentService().CreateComment(issueID, parentID, user.ID, req.Body)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessCreated,
+		"data":    comment,
+	})
+}
+
+// @Summary Get comments of an issue
+// @Description Retrieves all comments of an issue in chronological order, including soft-deleted placeholders.
+// @Tags Comments
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/comments/{issueID} [get]
+func GetCommentsHandler(c *fiber.Ctx) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	comments, err := service.GetCommentService().GetCommentsByIssueID(issueID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    comments,
+	})
+}
+
+// @Summary Edit a comment
+// @Description Updates the body of a comment and marks it as edited. Only the author can edit.
+// @Tags Comments
+// @Accept json
+// @Produce json
+// @Param commentID path string true "Comment ID"
+// @Param comment body request.CommentRequest true "Comment"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/update-comment/{commentID} [put]
+func UpdateCommentHandler(c *fiber.Ctx) error {
+	commentID, err := primitive.ObjectIDFromHex(c.Params("commentID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.CommentRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	comment, err := service.GetCommentService().UpdateComment(commentID, user.ID, req.Body)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    comment,
+	})
+}
+
+// @Summary Delete a comment
+// @Description Soft-deletes a comment. The author or the project owner can delete.
+// @Tags Comments
+// @Produce json
+// @Param commentID path string true "Comment ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/delete-comment/{commentID} [delete]
+func DeleteCommentHandler(c *fiber.Ctx) error {
+	commentID, err := primitive.ObjectIDFromHex(c.Params("commentID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	if err := service.GetCommentService().DeleteComment(commentID, user.ID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
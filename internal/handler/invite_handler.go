@@ -112,6 +112,18 @@ func GetInviteHandlerById(c *fiber.Ctx) error {
 		})
 	}
 
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+	if objID != user.ID && !user.IsAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": constant.ErrForbidden,
+		})
+	}
+
 	models, err := service.GetProjectInvites(objID)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
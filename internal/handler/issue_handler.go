@@ -2,6 +2,7 @@ package handler
 
 import (
 	"managify/constant"
+	"managify/dto/request"
 	"managify/internal/service"
 	"managify/models"
 	"managify/utils"
@@ -131,14 +132,15 @@ func DeleteIssueHandler(c *fiber.Ctx) error {
 }
 
 // @Summary Update issue status
-// @Description Updates the status of an issue.
+// @Description Updates the status of an issue. The move must be allowed by the project workflow, which may require a resolution note.
 // @Tags Issues
+// @Accept json
 // @Produce json
 // @Param issueID path string true "Issue ID"
 // @Param statusID path string true "New Status ID"
+// @Param body body request.IssueStatusRequest false "Resolution note"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
-// @Failure 401 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /issues/{issueID}/status/{statusID} [patch]
 func UpdateIssueStatusHandler(c *fiber.Ctx) error {
@@ -167,10 +169,20 @@ func UpdateIssueStatusHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	updatedIssue, err := service.GetIssueService().UpdateIssueStatus(issueID, newStatusID, user.ID)
+	var req request.IssueStatusRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+			})
+		}
+	}
+
+	updatedIssue, err := service.GetIssueService().UpdateIssueStatus(issueID, newStatusID, user.ID, req.Resolution)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"message": err.Error(),
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
 		})
 	}
 
@@ -223,3 +235,148 @@ func GetOncomingIssuesHandler(c *fiber.Ctx) error {
 		"data":    issueResponse,
 	})
 }
+
+// @Summary Update an issue
+// @Description Updates any subset of title, description, priority, due date and tags. Every changed field is recorded in the issue history. Status is rejected; move the issue with PUT /v1/board/move-issue/{issueID} instead.
+// @Tags Issues
+// @Accept json
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Param issue body request.IssueUpdateRequest true "Fields to update"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/update-issue/{issueID} [patch]
+func UpdateIssueHandler(c *fiber.Ctx) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.IssueUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issue, err := service.GetIssueService().UpdateIssue(issueID, user.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    issue,
+	})
+}
+
+// @Summary Get issue history
+// @Description Retrieves the field-level change history of an issue, newest first.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/history/{issueID} [get]
+func GetIssueHistoryHandler(c *fiber.Ctx) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	history, err := service.GetIssueHistoryService().GetHistoryByIssueID(issueID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    history,
+	})
+}
+
+// @Summary Query project issues
+// @Description Filters the issues of a project by priority, status, tags, due date range, assignee and free text, with cursor pagination and sorting.
+// @Tags Issues
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param priority query string false "Comma separated priorities"
+// @Param status query string false "Comma separated status types"
+// @Param status_id query string false "Status column ID"
+// @Param tags query string false "Comma separated tags, all must match"
+// @Param due_from query string false "Due date lower bound (YYYY-MM-DD)"
+// @Param due_to query string false "Due date upper bound (YYYY-MM-DD)"
+// @Param assignee query string false "Assignee ID, 'me' or 'none'"
+// @Param q query string false "Free text on title and description"
+// @Param sort query string false "created, updated, due_date or title"
+// @Param order query string false "asc or desc"
+// @Param limit query int false "Page size (max 100)"
+// @Param cursor query string false "Cursor returned by the previous page"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/query/{projectID} [get]
+func QueryIssuesHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.IssueQueryRequest
+	if err := c.QueryParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	res, err := service.GetIssueService().QueryIssues(projectID, user.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message":     constant.SuccessFetched,
+		"data":        res.Issues,
+		"next_cursor": res.NextCursor,
+	})
+}
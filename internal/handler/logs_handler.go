@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"managify/constant"
 	"managify/internal/service"
+	"managify/utils"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -26,6 +28,14 @@ func GetLogsHandlerByUserId(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": "User ID is required"})
 	}
 
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": constant.ErrInternalServer})
+	}
+	if userId != user.ID.Hex() && !user.IsAdmin {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"message": constant.ErrForbidden})
+	}
+
 	logs, err := service.GetLogService().GetLogsByUserId(userId)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"message": "Failed to fetch logs"})
@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Get notification preferences
+// @Description Returns which events are emailed to the authenticated user and whether they are sent instantly or as an hourly or daily digest.
+// @Tags Account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/notifications [get]
+func GetNotificationPreferencesHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	prefs, err := service.GetNotificationService().GetPreferences(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    prefs,
+	})
+}
+
+// @Summary Update notification preferences
+// @Description Replaces the muted email events (invite_received, invite_accepted, invite_declined, issue_assigned, issue_status_changed, issue_due_soon) and the digest frequency (instant, hourly or daily).
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.NotificationPreferencesRequest true "Muted events and digest frequency"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/notifications [put]
+func UpdateNotificationPreferencesHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.NotificationPreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	prefs, err := service.GetNotificationService().UpdatePreferences(user.ID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    prefs,
+	})
+}
+
+// @Summary Watch an issue
+// @Description Subscribes the authenticated user to status change notifications of the issue.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/watch/{issueID} [put]
+func WatchIssueHandler(c *fiber.Ctx) error {
+	return setWatching(c, true)
+}
+
+// @Summary Unwatch an issue
+// @Description Stops status change notifications of the issue for the authenticated user.
+// @Tags Issues
+// @Produce json
+// @Param issueID path string true "Issue ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /issue/watch/{issueID} [delete]
+func UnwatchIssueHandler(c *fiber.Ctx) error {
+	return setWatching(c, false)
+}
+
+func setWatching(c *fiber.Ctx, watch bool) error {
+	issueID, err := primitive.ObjectIDFromHex(c.Params("issueID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	issues := service.GetIssueService()
+	update := issues.UnwatchIssue
+	if watch {
+		update = issues.WatchIssue
+	}
+	issue, err := update(issueID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    issue,
+	})
+}
+
+// @Summary List notifications
+// @Description Returns the in-app notifications of the authenticated user, newest first. Pass the ID of the last notification as before to get the next page.
+// @Tags Notifications
+// @Produce json
+// @Param unread query bool false "Only unread notifications"
+// @Param before query string false "Return notifications older than this ID"
+// @Param limit query int false "Page size (max 100)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /notifications [get]
+func GetNotificationsHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var before primitive.ObjectID
+	if raw := c.Query("before"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+				"error":   "invalid before cursor",
+			})
+		}
+		before = id
+	}
+
+	inbox := service.GetInboxService()
+	notifications, err := inbox.GetNotifications(user.ID, c.QueryBool("unread"), before, c.QueryInt("limit"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+	unread, err := inbox.UnreadCount(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":      constant.SuccessFetched,
+		"data":         notifications,
+		"unread_count": unread,
+	})
+}
+
+// @Summary Get unread notification count
+// @Description Returns how many unread notifications the authenticated user has, e.g. for the bell icon badge.
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /notifications/unread-count [get]
+func GetUnreadCountHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	unread, err := service.GetInboxService().UnreadCount(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    fiber.Map{"unread_count": unread},
+	})
+}
+
+// @Summary Mark all notifications as read
+// @Description Marks every unread notification of the authenticated user as read.
+// @Tags Notifications
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /notifications/read-all [put]
+func MarkAllNotificationsReadHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	updated, err := service.GetInboxService().MarkAllRead(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    fiber.Map{"updated": updated},
+	})
+}
+
+// @Summary Mark a notification as read
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /notifications/{id}/read [put]
+func MarkNotificationReadHandler(c *fiber.Ctx) error {
+	return setNotificationRead(c, true)
+}
+
+// @Summary Mark a notification as unread
+// @Tags Notifications
+// @Produce json
+// @Param id path string true "Notification ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Security BearerAuth
+// @Router /notifications/{id}/unread [put]
+func MarkNotificationUnreadHandler(c *fiber.Ctx) error {
+	return setNotificationRead(c, false)
+}
+
+func setNotificationRead(c *fiber.Ctx, read bool) error {
+	id, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	notification, err := service.GetInboxService().SetRead(user.ID, id, read)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": constant.ErrNotFound,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    notification,
+	})
+}
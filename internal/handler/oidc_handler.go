@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Start single sign-on
+// @Description Returns the URL of the identity provider to send the browser to. The provider redirects back to the frontend with a code and state for the callback endpoint.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 404 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /users/auth/oidc [get]
+func StartOIDCLoginHandler(c *fiber.Ctx) error {
+	oidcService := service.GetOIDCService()
+	if !oidcService.Enabled() {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"message": constant.ErrNotFound,
+			"error":   "single sign-on is not configured",
+		})
+	}
+
+	authURL, err := oidcService.StartLogin()
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessOperation,
+		"data":    fiber.Map{"authorization_url": authURL},
+	})
+}
+
+// @Summary Complete single sign-on
+// @Description Exchanges the code and state the identity provider redirected back with for the session tokens. The account is created or linked by verified email on first use.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.OIDCCallbackRequest true "Code and state"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /users/auth/oidc/callback [post]
+func OIDCCallbackHandler(c *fiber.Ctx) error {
+	var req request.OIDCCallbackRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" || req.State == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	res, err := service.GetOIDCService().CompleteLogin(req.Code, req.State, sessionInfo(c))
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+			"error":   err.Error(),
+		})
+	}
+
+	return loginResponse(c, res)
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"fmt"
 	"managify/constant"
+	"managify/dto/request"
 	"managify/internal/service"
 	"managify/models"
 	"managify/utils"
@@ -15,7 +16,7 @@ import (
 )
 
 // @Summary Create a new project
-// @Description Creates a new project in the system.
+// @Description Creates a new project in the system. Setting template to the key of a project template seeds its statuses, tags and workflow.
 // @Tags Projects
 // @Accept json
 // @Produce json
@@ -44,8 +45,9 @@ func CreateProjectHandler(c *fiber.Ctx) error {
 
 	res, err := service.GetProjectService().CreateProject(&project, user)
 	if err != nil {
-		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
-			"message": constant.ErrUnauthorized,
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
 		})
 	}
 
@@ -56,10 +58,11 @@ func CreateProjectHandler(c *fiber.Ctx) error {
 }
 
 // @Summary Delete a project
-// @Description Deletes a project by its ID.
+// @Description Deletes a project by its ID. The project is kept restorable for the retention window unless permanent is set; permanent deletion cascades to all related data.
 // @Tags Projects
 // @Produce json
 // @Param id path string true "Project ID"
+// @Param permanent query bool false "Delete immediately without a restore window"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
@@ -83,7 +86,7 @@ func DeleteProjectHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	err = service.GetProjectService().DeleteProjectById(objID, user)
+	err = service.GetProjectService().DeleteProjectById(objID, user, c.QueryBool("permanent"))
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"message": constant.ErrUnauthorized,
@@ -197,15 +200,16 @@ func GetProjectHandler(c *fiber.Ctx) error {
 }
 
 // @Summary Delete a member from a project by member ID
-// @Description Deletes a member from a project using the member's ID.
+// @Description Removes a member from the project team together with their role. Requires member.remove.
 // @Tags Projects
 // @Produce json
+// @Param id path string true "Project ID"
 // @Param memberId path string true "Member ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
-// @Router /projects/member/{memberId} [delete]
+// @Router /projects/{id}/member/{memberId} [delete]
 func DeleteMemberFromProjectByIdHandler(c *fiber.Ctx) error {
 
 	memberId := c.Params("memberId")
@@ -216,23 +220,214 @@ func DeleteMemberFromProjectByIdHandler(c *fiber.Ctx) error {
 		})
 	}
 
+	projectIdObj, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": constant.ErrBadRequest})
+	}
+
 	memberIdObj, err := primitive.ObjectIDFromHex(memberId)
 
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"message": constant.ErrBadRequest})
 	}
 
-	err = service.GetProjectService().DeleteMemberFromProjectById(user.ID, memberIdObj)
+	err = service.GetProjectService().DeleteMemberFromProjectById(projectIdObj, user.ID, memberIdObj)
+
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
 
+// @Summary Update a project
+// @Description Updates the name, description, category or tags of a project. Only the owner or an admin can update, and archived projects are read-only.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param project body request.ProjectUpdateRequest true "Fields to update"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/update-project/{id} [patch]
+func UpdateProjectHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
 	if err != nil {
-		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.ProjectUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": constant.ErrInternalServer,
 		})
 	}
 
-	fmt.Print(err)
+	project, err := service.GetProjectService().UpdateProject(projectID, user, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"message": constant.SuccessDeleted,
+		"message": constant.SuccessUpdated,
+		"data":    project,
+	})
+}
+
+// @Summary Change project lifecycle status
+// @Description Moves a project to ACTIVE, ON_HOLD, ARCHIVED or COMPLETED. Archived projects are read-only.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param status body request.ProjectStatusRequest true "New status"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/project-status/{id} [put]
+func UpdateProjectStatusHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.ProjectStatusRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	project, err := service.GetProjectService().SetProjectStatus(projectID, user, models.ProjectStatus(req.Status))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    project,
+	})
+}
+
+// @Summary Restore a deleted project
+// @Description Restores a soft-deleted project before its retention window expires.
+// @Tags Projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/restore-project/{id} [put]
+func RestoreProjectHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	project, err := service.GetProjectService().RestoreProject(projectID, user)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    project,
+	})
+}
+
+// @Summary Get deleted projects
+// @Description Retrieves the soft-deleted projects of the user that can still be restored.
+// @Tags Projects
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/deleted-projects [get]
+func GetDeletedProjectsHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	projects, err := service.GetProjectService().GetDeletedProjects(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    projects,
+	})
+}
+
+// @Summary List project templates
+// @Description Returns the built-in and custom templates that can be used when creating a project.
+// @Tags Projects
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 500 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/templates [get]
+func GetProjectTemplatesHandler(c *fiber.Ctx) error {
+	templates, err := service.GetProjectTemplateService().GetTemplates()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    templates,
 	})
 }
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"bufio"
+	"managify/constant"
+	"managify/internal/realtime"
+	"managify/utils"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Stream project events
+// @Description Opens a Server-Sent Events stream of board changes in the project: issue.created, issue.updated, issue.moved, issue.deleted, status.created, status.deleted and member.joined. Browsers using EventSource can pass the access token as the access_token query parameter. The stream ends with an expired event when the access token expires.
+// @Tags Realtime
+// @Produce text/event-stream
+// @Param projectID path string true "Project ID"
+// @Param access_token query string false "Access token, if the Authorization header cannot be set"
+// @Success 200 {string} string "event stream"
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Security BearerAuth
+// @Router /realtime/projects/{projectID} [get]
+func ProjectEventsHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+	until, _ := c.Locals("token_exp").(time.Time)
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	sub := realtime.Default().Subscribe(projectID, user.ID)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		sub.Stream(w, until)
+	})
+	return nil
+}
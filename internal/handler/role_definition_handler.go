@@ -0,0 +1,188 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// @Summary Define a custom role
+// @Description Adds a named role with its own set of permissions to a project. Requires role.manage.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param definition body request.RoleDefinitionRequest true "Role name and permissions"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/create-definition/{projectID} [post]
+func CreateRoleDefinitionHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.RoleDefinitionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	definition, err := service.GetRoleDefinitionService().CreateDefinition(user.ID, projectID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": constant.SuccessCreated,
+		"data":    definition,
+	})
+}
+
+// @Summary List custom roles
+// @Description Returns the custom roles defined in a project.
+// @Tags Projects
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/definitions/{projectID} [get]
+func GetRoleDefinitionsHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	definitions, err := service.GetRoleDefinitionService().GetDefinitions(projectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    definitions,
+	})
+}
+
+// @Summary Update a custom role
+// @Description Renames a custom role or replaces its permissions. Requires role.manage.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param definitionID path string true "Role definition ID"
+// @Param definition body request.RoleDefinitionRequest true "Role name and permissions"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/update-definition/{projectID}/{definitionID} [put]
+func UpdateRoleDefinitionHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	definitionID, err := primitive.ObjectIDFromHex(c.Params("definitionID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.RoleDefinitionRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	definition, err := service.GetRoleDefinitionService().UpdateDefinition(user.ID, projectID, definitionID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    definition,
+	})
+}
+
+// @Summary Delete a custom role
+// @Description Removes a custom role. Members holding it fall back to MEMBER. Requires role.manage.
+// @Tags Projects
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param definitionID path string true "Role definition ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/delete-definition/{projectID}/{definitionID} [delete]
+func DeleteRoleDefinitionHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	definitionID, err := primitive.ObjectIDFromHex(c.Params("definitionID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	if err := service.GetRoleDefinitionService().DeleteDefinition(user.ID, projectID, definitionID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
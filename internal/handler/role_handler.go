@@ -1,9 +1,8 @@
 package handler
 
 import (
-	"fmt"
-
 	"managify/constant"
+	"managify/dto/request"
 	"managify/internal/service"
 	"managify/models"
 	"managify/utils"
@@ -13,7 +12,7 @@ import (
 )
 
 // @Summary Create a new role
-// @Description Creates a new role in a project. Only project owners can add roles.
+// @Description Gives a team member a built-in role (MAINTAINER, MEMBER or VIEWER) or a custom role of the project. Requires role.manage.
 // @Tags Projects
 // @Accept json
 // @Produce json
@@ -44,25 +43,11 @@ func CreateRoleHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	fmt.Println(user.ID)
-
-	isOwner, err := service.GetProjectService().IsOwner(user.ID, roleProjectID)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": constant.ErrInternalServer,
-		})
-	}
-	if !isOwner {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"message": constant.ErrForbidden,
-			"error":   "You are not owner",
-		})
-	}
-
-	res, err := service.GetRoleService().AddRole(roleUserID, roleProjectID, RoleName)
+	res, err := service.GetRoleService().AddRole(user.ID, roleUserID, roleProjectID, RoleName)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
 		})
 	}
 
@@ -73,15 +58,16 @@ func CreateRoleHandler(c *fiber.Ctx) error {
 }
 
 // @Summary Delete a role
-// @Description Deletes a role by its ID.
+// @Description Deletes a role assignment of a project, returning the member to the default MEMBER role. Requires role.manage.
 // @Tags Projects
 // @Produce json
 // @Param id path string true "Role ID"
+// @Param project path string true "Project ID"
 // @Success 200 {object} map[string]interface{}
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security BearerAuth
-// @Router /roles/{id} [delete]
+// @Router /roles/{id}/{project} [delete]
 func DeleteRoleHandler(c *fiber.Ctx) error {
 	idParam := c.Params("id")
 	if idParam == "" {
@@ -99,14 +85,116 @@ func DeleteRoleHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	err = service.GetRoleService().DeleteRole(roleID)
+	projectID, err := primitive.ObjectIDFromHex(c.Params("project"))
 	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   "invalid project format",
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": constant.ErrInternalServer,
 		})
 	}
 
+	err = service.GetRoleService().DeleteRole(roleID, projectID, user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"message": constant.SuccessDeleted,
 	})
 }
+
+// @Summary List project members with roles
+// @Description Retrieves the owner and team of a project with the role of each member.
+// @Tags Projects
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/members/{projectID} [get]
+func GetProjectMembersHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	members, err := service.GetRoleService().GetProjectMembers(projectID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    members,
+	})
+}
+
+// @Summary Change a member's role
+// @Description Sets the role of a team member. Requires role.manage.
+// @Tags Projects
+// @Accept json
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param userID path string true "Member ID"
+// @Param role body request.MemberRoleRequest true "New role"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /role/member-role/{projectID}/{userID} [put]
+func UpdateMemberRoleHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	memberID, err := primitive.ObjectIDFromHex(c.Params("userID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.MemberRoleRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	role, err := service.GetRoleService().AddRole(user.ID, memberID, projectID, req.Role)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    role,
+	})
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Search projects, issues and comments
+// @Description Runs a ranked full-text search over the projects the user owns or belongs to, grouped by entity type.
+// @Tags Search
+// @Produce json
+// @Param q query string true "Search text"
+// @Param limit query int false "Maximum results per entity type (max 50)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /search [get]
+func SearchHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	res, err := service.GetSearchService().Search(user.ID, c.Query("q"), c.QueryInt("limit"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    res,
+	})
+}
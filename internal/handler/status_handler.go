@@ -2,6 +2,7 @@ package handler
 
 import (
 	"managify/constant"
+	"managify/dto/request"
 	"managify/internal/service"
 	"managify/models"
 	"managify/utils"
@@ -99,6 +100,7 @@ func DeleteStatusHandler(c *fiber.Ctx) error {
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
 		})
 	}
 
@@ -106,3 +108,81 @@ func DeleteStatusHandler(c *fiber.Ctx) error {
 		"message": constant.SuccessDeleted,
 	})
 }
+
+// @Summary Get project workflow
+// @Description Returns the allowed transitions between the statuses of a project. An empty list allows every move.
+// @Tags Statuses
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /status/workflow/{projectID} [get]
+func GetWorkflowHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	workflow, err := service.GetWorkflowService().GetWorkflow(projectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    workflow,
+	})
+}
+
+// @Summary Set project workflow
+// @Description Replaces the transition rules of a project. Each transition can require fields (resolution, assignee, due_date, description) and restrict the roles allowed to make it. Requires status.manage.
+// @Tags Statuses
+// @Accept json
+// @Produce json
+// @Param projectID path string true "Project ID"
+// @Param workflow body request.WorkflowRequest true "Transitions"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /status/workflow/{projectID} [put]
+func SetWorkflowHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("projectID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.WorkflowRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	workflow, err := service.GetWorkflowService().SetWorkflow(user.ID, projectID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    workflow,
+	})
+}
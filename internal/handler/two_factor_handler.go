@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// @Summary Complete two-factor login
+// @Description Exchanges the challenge token returned by the login endpoint and an authenticator or recovery code for the session tokens.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.TwoFactorLoginRequest true "Challenge token and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /users/auth/2fa [post]
+func TwoFactorLoginHandler(c *fiber.Ctx) error {
+	var req request.TwoFactorLoginRequest
+	if err := c.BodyParser(&req); err != nil || req.ChallengeToken == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	res, err := service.GetTwoFactorService().CompleteLogin(req.ChallengeToken, req.Code, sessionInfo(c))
+	if err != nil {
+		var blocked *service.LoginBlockedError
+		if errors.As(err, &blocked) {
+			return loginError(c, err)
+		}
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+			"error":   err.Error(),
+		})
+	}
+
+	return loginResponse(c, res)
+}
+
+// @Summary Start two-factor setup
+// @Description Generates a new TOTP secret and its otpauth URI for an authenticator app. Two-factor login is enabled once a code is confirmed.
+// @Tags Account
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/2fa/setup [post]
+func SetupTwoFactorHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	setup, err := service.GetTwoFactorService().Setup(user.ID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessOperation,
+		"data":    setup,
+	})
+}
+
+// @Summary Enable two-factor login
+// @Description Confirms the setup with a code from the authenticator app and returns the recovery codes. They are shown only once.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.TwoFactorCodeRequest true "Authenticator code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/2fa/enable [post]
+func EnableTwoFactorHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	codes, err := service.GetTwoFactorService().Enable(user.ID, req.Code)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    fiber.Map{"recovery_codes": codes},
+	})
+}
+
+// @Summary Disable two-factor login
+// @Description Turns off two-factor login after checking the password and an authenticator or recovery code.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.TwoFactorDisableRequest true "Password and code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/2fa/disable [post]
+func DisableTwoFactorHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.TwoFactorDisableRequest
+	if err := c.BodyParser(&req); err != nil || req.Password == "" || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetTwoFactorService().Disable(user.ID, req.Password, req.Code); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+	})
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replaces every recovery code. Requires an authenticator or recovery code.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param body body request.TwoFactorCodeRequest true "Authenticator or recovery code"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/me/2fa/recovery-codes [post]
+func RegenerateRecoveryCodesHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.TwoFactorCodeRequest
+	if err := c.BodyParser(&req); err != nil || req.Code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	codes, err := service.GetTwoFactorService().RegenerateRecoveryCodes(user.ID, req.Code)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    fiber.Map{"recovery_codes": codes},
+	})
+}
@@ -1,10 +1,14 @@
 package handler
 
 import (
+	"errors"
 	"managify/constant"
 	"managify/dto/request"
+	"managify/dto/response"
 	"managify/internal/service"
 	"managify/models"
+	"managify/utils"
+	"strconv"
 	"sync"
 	"time"
 
@@ -106,21 +110,225 @@ func LoginHandler(c *fiber.Ctx) error {
 		})
 	}
 
-	res, err := service.GetUserService().Login(&req)
+	res, err := service.GetUserService().Login(&req, sessionInfo(c))
+	if err != nil {
+		return loginError(c, err)
+	}
+
+	return loginResponse(c, res)
+}
+
+// loginError answers a failed login, with 429 and Retry-After while further
+// attempts are blocked.
+func loginError(c *fiber.Ctx, err error) error {
+	var blocked *service.LoginBlockedError
+	if errors.As(err, &blocked) {
+		seconds := int(blocked.RetryAfter.Round(time.Second).Seconds())
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(seconds))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+			"message":     constant.ErrTooManyRequests,
+			"error":       err.Error(),
+			"retry_after": seconds,
+		})
+	}
+
+	return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+		"message": constant.ErrUnauthorized,
+	})
+}
+
+// loginResponse answers a login either with the session tokens or with the
+// challenge of the two-factor step.
+func loginResponse(c *fiber.Ctx, res *response.UserLoginResponse) error {
+	if res.TwoFactorRequired {
+		return c.Status(fiber.StatusOK).JSON(fiber.Map{
+			"message":             "Two-factor authentication required",
+			"email":               res.Email,
+			"two_factor_required": true,
+			"challenge_token":     res.ChallengeToken,
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message":       constant.SuccessOperation,
+		"email":         res.Email,
+		"name":          res.FullName,
+		"token":         res.Token,
+		"refresh_token": res.RefreshToken,
+	})
+}
+
+// @Summary Refresh access token
+// @Description Exchanges a refresh token for a new access token and a new refresh token. Each refresh token can be used once; reusing one revokes the whole session.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.RefreshTokenRequest true "Refresh token"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Router /users/refresh [post]
+func RefreshTokenHandler(c *fiber.Ctx) error {
+	var req request.RefreshTokenRequest
+	if err := c.BodyParser(&req); err != nil || req.RefreshToken == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	pair, err := service.GetAuthService().Refresh(req.RefreshToken, sessionInfo(c))
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"message": constant.ErrUnauthorized,
+			"error":   err.Error(),
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+	return c.JSON(fiber.Map{
+		"message":       constant.SuccessOperation,
+		"token":         pair.AccessToken,
+		"refresh_token": pair.RefreshToken,
+	})
+}
+
+// @Summary Log out
+// @Description Revokes the current access token and, if given, the session of the refresh token.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.RefreshTokenRequest false "Refresh token of the session"
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/logout [post]
+func LogoutHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	var req request.RefreshTokenRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+			})
+		}
+	}
+
+	jti, _ := c.Locals("token_jti").(string)
+	expiresAt, _ := c.Locals("token_exp").(time.Time)
+
+	if err := service.GetAuthService().Logout(user.ID, jti, expiresAt, req.RefreshToken); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessOperation,
+	})
+}
+
+// @Summary Log out of all sessions
+// @Description Revokes every refresh token of the user and every access token issued so far.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]string
+// @Security BearerAuth
+// @Router /users/logout-all [post]
+func LogoutAllHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	if err := service.GetAuthService().LogoutAll(user.ID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
 		"message": constant.SuccessOperation,
-		"email":   res.Email,
-		"name":    res.FullName,
-		"token":   res.Token,
 	})
 }
 
+// @Summary Request a password reset
+// @Description Emails a single-use password reset link if an account with the address exists. Always succeeds so registered emails are not revealed.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.PasswordResetRequest true "Account email"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Router /users/password-reset/request [post]
+func RequestPasswordResetHandler(c *fiber.Ctx) error {
+	var req request.PasswordResetRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	// Failures are logged by the service; answering differently would reveal
+	// that the email is registered.
+	_ = service.GetPasswordResetService().RequestReset(req.Email)
+
+	return c.JSON(fiber.Map{
+		"message": "If the email is registered, a reset link has been sent",
+	})
+}
+
+// @Summary Confirm a password reset
+// @Description Sets a new password using the emailed reset token. All existing sessions are logged out.
+// @Tags Users
+// @Accept json
+// @Produce json
+// @Param body body request.PasswordResetConfirmRequest true "Reset token and new password"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /users/password-reset/confirm [post]
+func ConfirmPasswordResetHandler(c *fiber.Ctx) error {
+	var req request.PasswordResetConfirmRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	if err := service.GetPasswordResetService().ConfirmReset(req.Token, req.Password); err != nil {
+		if service.IsInvalidResetToken(err) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": constant.ErrBadRequest,
+				"error":   err.Error(),
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+	})
+}
+
+func sessionInfo(c *fiber.Ctx) service.SessionInfo {
+	return service.SessionInfo{
+		UserAgent: c.Get(fiber.HeaderUserAgent),
+		IP:        c.IP(),
+	}
+}
+
 // @Summary Get user by ID
 // @Description Retrieves a user by their ID, along with associated projects and subscription details.
 // @Tags Users
@@ -177,11 +385,10 @@ func GetUserByIdHandler(c *fiber.Ctx) error {
 	}
 
 	data := fiber.Map{
-		"user":            user,
-		"isVerified":      user.IsVerified,
-		"validationToken": user.VerificationToken,
-		"project":         project,
-		"subscription":    sub,
+		"user":         user,
+		"isVerified":   user.IsVerified,
+		"project":      project,
+		"subscription": sub,
 	}
 
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
@@ -212,3 +419,43 @@ func VerifyEmailHandler(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{"message": "Email verified", "user": user.Email})
 }
+
+// @Summary Resend verification email
+// @Description Sends a new verification link to the authenticated, unverified user. The previous link stops working. Limited to one email per cooldown period.
+// @Tags Users
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 429 {object} map[string]interface{}
+// @Security BearerAuth
+// @Router /users/resend-verification [post]
+func ResendVerificationHandler(c *fiber.Ctx) error {
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	err := service.GetUserService().ResendVerification(user.ID)
+	if err != nil {
+		var tooSoon *service.ResendTooSoonError
+		if errors.As(err, &tooSoon) {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(tooSoon.RetryAfter.Seconds())))
+			return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{
+				"message":     constant.ErrTooManyRequests,
+				"error":       err.Error(),
+				"retry_after": int(tooSoon.RetryAfter.Seconds()),
+			})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Verification email sent",
+	})
+}
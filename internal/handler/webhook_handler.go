@@ -0,0 +1,264 @@
+package handler
+
+import (
+	"managify/constant"
+	"managify/dto/request"
+	"managify/internal/service"
+	"managify/utils"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// webhookParams parses the project and webhook IDs of a webhook route.
+func webhookParams(c *fiber.Ctx) (primitive.ObjectID, primitive.ObjectID, bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	webhookID, err := primitive.ObjectIDFromHex(c.Params("webhookID"))
+	if err != nil {
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+	return projectID, webhookID, true
+}
+
+// @Summary Create a webhook
+// @Description Subscribes a URL to the project's events. Every delivery is signed with the secret, which is generated when omitted and only returned here.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param body body request.WebhookCreateRequest true "URL, secret and event filter"
+// @Success 201 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks [post]
+func CreateWebhookHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	var req request.WebhookCreateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	hook, secret, err := service.GetWebhookService().CreateWebhook(projectID, user.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": constant.SuccessCreated,
+		"data":    fiber.Map{"webhook": hook, "secret": secret},
+	})
+}
+
+// @Summary List webhooks
+// @Description Returns the webhooks of the project without their secrets.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks [get]
+func GetWebhooksHandler(c *fiber.Ctx) error {
+	projectID, err := primitive.ObjectIDFromHex(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	hooks, err := service.GetWebhookService().GetWebhooks(projectID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    hooks,
+	})
+}
+
+// @Summary Update a webhook
+// @Description Changes the URL, secret, event filter or active flag of a webhook. Omitted fields are left unchanged.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param webhookID path string true "Webhook ID"
+// @Param body body request.WebhookUpdateRequest true "Fields to change"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks/{webhookID} [patch]
+func UpdateWebhookHandler(c *fiber.Ctx) error {
+	projectID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	var req request.WebhookUpdateRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	hook, err := service.GetWebhookService().UpdateWebhook(projectID, webhookID, user.ID, &req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessUpdated,
+		"data":    hook,
+	})
+}
+
+// @Summary Delete a webhook
+// @Description Removes a webhook and its delivery records.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param webhookID path string true "Webhook ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks/{webhookID} [delete]
+func DeleteWebhookHandler(c *fiber.Ctx) error {
+	projectID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	user, ok := utils.GetUserLocal(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+		})
+	}
+
+	if err := service.GetWebhookService().DeleteWebhook(projectID, webhookID, user.ID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessDeleted,
+	})
+}
+
+// @Summary List webhook deliveries
+// @Description Returns the most recent deliveries of a webhook with their payload and attempt history.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param webhookID path string true "Webhook ID"
+// @Param limit query int false "Maximum number of deliveries (default 50, max 200)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks/{webhookID}/deliveries [get]
+func GetWebhookDeliveriesHandler(c *fiber.Ctx) error {
+	projectID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	limit := c.QueryInt("limit", 50)
+	if limit <= 0 || limit > 200 {
+		limit = 50
+	}
+
+	deliveries, err := service.GetWebhookService().GetDeliveries(projectID, webhookID, int64(limit))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessFetched,
+		"data":    deliveries,
+	})
+}
+
+// @Summary Redeliver a webhook delivery
+// @Description Sends a delivered or failed delivery again with the same payload and a fresh set of retries.
+// @Tags Webhooks
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param webhookID path string true "Webhook ID"
+// @Param deliveryID path string true "Delivery ID"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Security BearerAuth
+// @Router /project/projects/{id}/webhooks/{webhookID}/deliveries/{deliveryID}/redeliver [post]
+func RedeliverWebhookHandler(c *fiber.Ctx) error {
+	projectID, webhookID, ok := webhookParams(c)
+	if !ok {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+	deliveryID, err := primitive.ObjectIDFromHex(c.Params("deliveryID"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+		})
+	}
+
+	delivery, err := service.GetWebhookService().Redeliver(projectID, webhookID, deliveryID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": constant.ErrBadRequest,
+			"error":   err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": constant.SuccessOperation,
+		"data":    delivery,
+	})
+}
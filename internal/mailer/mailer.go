@@ -0,0 +1,118 @@
+// Package mailer renders transactional emails from templates and delivers
+// them through a pluggable Mailer: SMTP in production, a log or file sink in
+// development and an in-memory mailer in tests.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var log = logrus.New()
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+}
+
+// Message is a rendered email with an HTML and a plain text body.
+type Message struct {
+	To      string
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Mailer delivers a single message.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// ErrNotConfigured is returned by FromEnv when no transport is selected.
+var ErrNotConfigured = errors.New("no mail transport is configured, set SMTP_HOST or MAIL_DRIVER")
+
+// FromEnv returns the mailer selected by MAIL_DRIVER: smtp, file, log or
+// memory. Without MAIL_DRIVER SMTP is used when SMTP_HOST is set. The log and
+// file sinks print live links, so they are never chosen implicitly.
+func FromEnv() (Mailer, error) {
+	switch driver := strings.ToLower(os.Getenv("MAIL_DRIVER")); driver {
+	case "smtp":
+		return SMTPFromEnv(), nil
+	case "file":
+		dir := os.Getenv("MAIL_FILE_DIR")
+		if dir == "" {
+			dir = "mail"
+		}
+		return &LogMailer{Dir: dir}, nil
+	case "log":
+		return &LogMailer{}, nil
+	case "memory":
+		return &MemoryMailer{}, nil
+	case "":
+		if os.Getenv("SMTP_HOST") != "" {
+			return SMTPFromEnv(), nil
+		}
+		return nil, ErrNotConfigured
+	default:
+		return nil, fmt.Errorf("unknown MAIL_DRIVER %q: %w", driver, ErrNotConfigured)
+	}
+}
+
+// Bytes encodes msg as a multipart/alternative MIME message.
+func (msg Message) Bytes(from string) ([]byte, error) {
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	headers := []string{
+		"From: " + from,
+		"To: " + msg.To,
+		"Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject),
+		"Date: " + time.Now().Format(time.RFC1123Z),
+		"MIME-Version: 1.0",
+		"Content-Type: multipart/alternative; boundary=" + body.Boundary(),
+	}
+	var out bytes.Buffer
+	out.WriteString(strings.Join(headers, "\r\n") + "\r\n\r\n")
+
+	for _, part := range []struct{ contentType, content string }{
+		{"text/plain; charset=UTF-8", msg.Text},
+		{"text/html; charset=UTF-8", msg.HTML},
+	} {
+		if part.content == "" {
+			continue
+		}
+		w, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.content)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := body.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode message: %w", err)
+	}
+
+	out.Write(buf.Bytes())
+	return out.Bytes(), nil
+}
@@ -0,0 +1,145 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+const testLink = "https://app.example.com/reset?token=a1b2&x=<y>"
+
+func TestRenderTemplates(t *testing.T) {
+	data := map[string]any{
+		"Name":    "Jane <Doe>",
+		"Link":    testLink,
+		"Hours":   24,
+		"Minutes": 30,
+		"Title":   "Issue moved",
+		"Message": "Fix login moved to Done",
+		"Items": []map[string]any{
+			{"Title": "Issue moved", "Message": "Fix login moved to Done", "Link": testLink},
+		},
+	}
+
+	for _, name := range templateNames {
+		t.Run(name, func(t *testing.T) {
+			msg, err := Render(name, "jane@example.com", data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.To != "jane@example.com" || msg.Subject == "" || strings.Contains(msg.Subject, "\n") {
+				t.Errorf("to %q, subject %q", msg.To, msg.Subject)
+			}
+			if !strings.Contains(msg.Text, testLink) || !strings.Contains(msg.Text, "Jane <Doe>") {
+				t.Errorf("text body lacks the raw link or name:\n%s", msg.Text)
+			}
+			if strings.Contains(msg.HTML, "<Doe>") || strings.Contains(msg.HTML, "<y>") {
+				t.Errorf("html body is not escaped:\n%s", msg.HTML)
+			}
+			if !strings.Contains(msg.HTML, "Jane &lt;Doe&gt;") {
+				t.Errorf("html body lacks the escaped name:\n%s", msg.HTML)
+			}
+		})
+	}
+}
+
+func TestRenderSubjects(t *testing.T) {
+	msg, err := Render(TemplateDigest, "jane@example.com", map[string]any{
+		"Name":  "Jane",
+		"Items": []map[string]any{{"Title": "a"}, {"Title": "b"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg.Subject != "Your Managify updates: 2 new" {
+		t.Errorf("subject = %q", msg.Subject)
+	}
+}
+
+func TestRenderUnknownTemplate(t *testing.T) {
+	if _, err := Render("missing", "jane@example.com", nil); err == nil {
+		t.Fatal("unknown template rendered")
+	}
+}
+
+func TestMessageBytes(t *testing.T) {
+	msg, err := Render(TemplatePasswordReset, "jane@example.com", map[string]any{"Name": "Jane", "Link": testLink, "Minutes": 30})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := msg.Bytes("Managify <noreply@example.com>")
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := string(data)
+	for _, want := range []string{
+		"From: Managify <noreply@example.com>\r\n",
+		"To: jane@example.com\r\n",
+		"Subject: Reset your password\r\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Content-Type: text/plain; charset=UTF-8",
+		"Content-Type: text/html; charset=UTF-8",
+	} {
+		if !strings.Contains(raw, want) {
+			t.Errorf("message lacks %q", want)
+		}
+	}
+}
+
+func TestMemoryMailer(t *testing.T) {
+	m := &MemoryMailer{}
+	ctx := context.Background()
+
+	m.Err = errors.New("smtp down")
+	if err := m.Send(ctx, Message{To: "a@example.com"}); err == nil {
+		t.Fatal("Send ignored Err")
+	}
+	m.Err = nil
+	if err := m.Send(ctx, Message{To: "b@example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	if sent := m.Sent(); len(sent) != 1 || sent[0].To != "b@example.com" {
+		t.Fatalf("sent = %+v", sent)
+	}
+	m.Reset()
+	if len(m.Sent()) != 0 {
+		t.Fatal("Reset kept messages")
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		driver, host string
+		want         string
+		err          bool
+	}{
+		{"", "", "", true},
+		{"", "smtp.example.com", "*mailer.SMTPMailer", false},
+		{"smtp", "smtp.example.com", "*mailer.SMTPMailer", false},
+		{"log", "", "*mailer.LogMailer", false},
+		{"FILE", "", "*mailer.LogMailer", false},
+		{"memory", "", "*mailer.MemoryMailer", false},
+		{"sendmail", "smtp.example.com", "", true},
+	}
+	for _, tt := range tests {
+		t.Setenv("MAIL_DRIVER", tt.driver)
+		t.Setenv("SMTP_HOST", tt.host)
+
+		m, err := FromEnv()
+		if tt.err {
+			if !errors.Is(err, ErrNotConfigured) || m != nil {
+				t.Errorf("driver %q host %q: %T, %v, want ErrNotConfigured", tt.driver, tt.host, m, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("driver %q host %q: %v", tt.driver, tt.host, err)
+			continue
+		}
+		if got := fmt.Sprintf("%T", m); got != tt.want {
+			t.Errorf("driver %q host %q: %s, want %s", tt.driver, tt.host, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+)
+
+// LogMailer is the development mailer. It logs every message with its text
+// body and, when Dir is set, also writes it there as an .eml file.
+type LogMailer struct {
+	Dir string
+}
+
+var unsafeFileChars = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	entry := log.WithField("to", msg.To).WithField("subject", msg.Subject)
+
+	if m.Dir == "" {
+		entry.Infof("Email not sent, logged instead:\n%s", msg.Text)
+		return nil
+	}
+
+	if err := os.MkdirAll(m.Dir, 0o755); err != nil {
+		return err
+	}
+	data, err := msg.Bytes("Managify <noreply@localhost>")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%s-%s.eml", time.Now().Format("20060102-150405.000000"), unsafeFileChars.ReplaceAllString(msg.To, "_"))
+	path := filepath.Join(m.Dir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return err
+	}
+	entry.Infof("Email written to %s", path)
+	return nil
+}
+
+// MemoryMailer keeps sent messages in memory for tests. While Err is set,
+// Send fails with it and keeps nothing.
+type MemoryMailer struct {
+	Err error
+
+	mu   sync.Mutex
+	sent []Message
+}
+
+func (m *MemoryMailer) Send(ctx context.Context, msg Message) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Err != nil {
+		return m.Err
+	}
+	m.sent = append(m.sent, msg)
+	return nil
+}
+
+// Sent returns a copy of the messages sent so far.
+func (m *MemoryMailer) Sent() []Message {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Message(nil), m.sent...)
+}
+
+// Reset forgets the sent messages.
+func (m *MemoryMailer) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = nil
+}
@@ -0,0 +1,105 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+// Message types. Each has a <name>.html body rendered inside layout.html and
+// a <name>.txt body that also defines the "subject" template.
+const (
+	TemplateVerification  = "verification"
+	TemplatePasswordReset = "password_reset"
+	TemplateEmailChange   = "email_change"
+	TemplateNotification  = "notification"
+	TemplateDigest        = "notification_digest"
+)
+
+// AppName is passed to every template as .AppName.
+const AppName = "Managify"
+
+//go:embed templates
+var templateFS embed.FS
+
+var templateNames = []string{
+	TemplateVerification, TemplatePasswordReset, TemplateEmailChange,
+	TemplateNotification, TemplateDigest,
+}
+
+// HasOneTimeLink reports whether emails of the template carry a link that
+// grants access on its own, such as a password reset.
+func HasOneTimeLink(template string) bool {
+	switch template {
+	case TemplateVerification, TemplatePasswordReset, TemplateEmailChange:
+		return true
+	}
+	return false
+}
+
+var (
+	htmlTemplates = map[string]*htmltemplate.Template{}
+	textTemplates = map[string]*texttemplate.Template{}
+)
+
+var templateFuncs = map[string]any{
+	// dict builds a map from key value pairs, e.g. for the button template.
+	"dict": func(pairs ...any) (map[string]any, error) {
+		if len(pairs)%2 != 0 {
+			return nil, fmt.Errorf("dict needs key value pairs")
+		}
+		m := make(map[string]any, len(pairs)/2)
+		for i := 0; i < len(pairs); i += 2 {
+			key, ok := pairs[i].(string)
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings")
+			}
+			m[key] = pairs[i+1]
+		}
+		return m, nil
+	},
+}
+
+func init() {
+	for _, name := range templateNames {
+		htmlTemplates[name] = htmltemplate.Must(htmltemplate.New("layout.html").Funcs(templateFuncs).
+			ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html"))
+		textTemplates[name] = texttemplate.Must(texttemplate.New(name+".txt").
+			ParseFS(templateFS, "templates/"+name+".txt"))
+	}
+}
+
+// Render builds the message of the given type for the recipient.
+func Render(name, to string, data map[string]any) (Message, error) {
+	htmlTmpl, ok := htmlTemplates[name]
+	if !ok {
+		return Message{}, fmt.Errorf("unknown email template %s", name)
+	}
+	textTmpl := textTemplates[name]
+
+	values := map[string]any{"AppName": AppName}
+	for k, v := range data {
+		values[k] = v
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := textTmpl.ExecuteTemplate(&subject, "subject", values); err != nil {
+		return Message{}, err
+	}
+	if err := textTmpl.Execute(&text, values); err != nil {
+		return Message{}, err
+	}
+	if err := htmlTmpl.Execute(&html, values); err != nil {
+		return Message{}, err
+	}
+
+	return Message{
+		To:      to,
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+	}, nil
+}
@@ -4,12 +4,23 @@ import (
 	"fmt"
 	"managify/constant"
 	"strings"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang-jwt/jwt/v5"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// TokenRevoked reports whether an access token has been revoked, either by
+// its jti or because the user logged out everywhere after issuedAt. It is
+// wired up by main; while nil every valid token is accepted.
+var TokenRevoked func(jti string, userID primitive.ObjectID, issuedAt time.Time) (bool, error)
+
 func AuthMiddleware(c *fiber.Ctx) error {
+	if key := apiKeyFromRequest(c); key != "" {
+		return authenticateAPIKey(c, key)
+	}
+
 	authHeader := c.Get("Authorization")
 	if authHeader == "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -52,7 +63,43 @@ func AuthMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
+	jti, _ := claims["jti"].(string)
+	issuedAt, _ := claims.GetIssuedAt()
+	expiresAt, _ := claims.GetExpirationTime()
+	if TokenRevoked != nil && issuedAt != nil {
+		revoked, err := TokenRevoked(jti, user.ID, issuedAt.Time)
+		if err != nil {
+			log.WithError(err).Error("failed to check token revocation")
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"message": constant.ErrInternalServer,
+			})
+		}
+		if revoked {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"message": constant.ErrUnauthorized,
+			})
+		}
+	}
+
+	blocked, err := unverifiedBlocked(c, user, claimToBool(claims, "is_verified", false))
+	if err != nil {
+		log.WithError(err).Error("failed to check email verification")
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"message": constant.ErrInternalServer,
+		})
+	}
+	if blocked {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"message": constant.ErrEmailUnverified,
+		})
+	}
+
 	c.Locals("user", user)
+	c.Locals("auth_method", "jwt")
+	c.Locals("token_jti", jti)
+	if expiresAt != nil {
+		c.Locals("token_exp", expiresAt.Time)
+	}
 
 	return c.Next()
 }
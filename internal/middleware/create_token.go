@@ -1,39 +1,37 @@
 package middleware
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"managify/models"
 	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
-	"github.com/sirupsen/logrus"
 )
 
 var secretKey = []byte(os.Getenv("SECRET_KEY"))
 
 func CreateToken(user *models.User) (string, error) {
+	jti, err := newTokenID()
+	if err != nil {
+		return "", err
+	}
 
-	var log = logrus.New()
-	log.SetFormatter(&logrus.TextFormatter{
-		FullTimestamp: true,
-		ForceColors:   true,
-	})
 	mapClaims := jwt.MapClaims{
-		"id":       user.ID,
-		"name":     user.FullName,
-		"email":    user.Email,
-		"is_admin": user.IsAdmin,
-		"iss":      "user",
-		"exp":      time.Now().Add(time.Hour).Unix(),
-		"iat":      time.Now().Unix(),
+		"jti":         jti,
+		"id":          user.ID,
+		"name":        user.FullName,
+		"email":       user.Email,
+		"is_admin":    user.IsAdmin,
+		"is_verified": user.IsVerified,
+		"iss":         "user",
+		"exp":         time.Now().Add(time.Hour).Unix(),
+		"iat":         time.Now().Unix(),
 	}
 
 	claims := jwt.NewWithClaims(jwt.SigningMethodHS256, mapClaims)
 
-	log.Info(claims)
-	log.Info(claims.Header)
-	log.Info(claims.Signature)
-
 	tokenString, err := claims.SignedString(secretKey)
 	if err != nil {
 		return "", err
@@ -42,3 +40,11 @@ func CreateToken(user *models.User) (string, error) {
 	return tokenString, nil
 
 }
+
+func newTokenID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
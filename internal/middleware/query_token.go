@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"managify/constant"
+	"managify/models"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// QueryToken lets clients that cannot set headers, such as the browser
+// EventSource, pass the access token as the access_token query parameter.
+// Only short-lived access tokens are accepted there: URLs end up in browser
+// history and proxy logs, so long-lived API keys must be sent as a header.
+// It must run before AuthMiddleware.
+func QueryToken(c *fiber.Ctx) error {
+	token := c.Query("access_token")
+	if token == "" || c.Get("Authorization") != "" || c.Get("X-API-Key") != "" {
+		return c.Next()
+	}
+	if strings.HasPrefix(token, models.APIKeyPrefix) {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"message": constant.ErrUnauthorized,
+			"error":   "API keys are not accepted in the URL, send them in the X-API-Key header",
+		})
+	}
+	c.Request().Header.Set("Authorization", "Bearer "+token)
+	return c.Next()
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"io"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TestQueryToken(t *testing.T) {
+	app := fiber.New()
+	app.Get("/events", QueryToken, func(c *fiber.Ctx) error {
+		return c.SendString(c.Get("Authorization"))
+	})
+
+	tests := []struct {
+		name   string
+		url    string
+		header string
+		status int
+		auth   string
+	}{
+		{"jwt", "/events?access_token=eyJhbGci.a.b", "", fiber.StatusOK, "Bearer eyJhbGci.a.b"},
+		{"api key", "/events?access_token=mfy_secret", "", fiber.StatusUnauthorized, ""},
+		{"header wins", "/events?access_token=eyJhbGci.a.b", "Bearer header", fiber.StatusOK, "Bearer header"},
+		{"no token", "/events", "", fiber.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tt.url, nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if res.StatusCode != tt.status {
+				t.Fatalf("status = %d, want %d", res.StatusCode, tt.status)
+			}
+			if tt.status != fiber.StatusOK {
+				return
+			}
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(body); got != tt.auth {
+				t.Errorf("Authorization = %q, want %q", got, tt.auth)
+			}
+		})
+	}
+}
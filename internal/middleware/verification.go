@@ -0,0 +1,79 @@
+package middleware
+
+import (
+	"managify/models"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VerificationPolicy decides which requests of unverified users
+// AuthMiddleware rejects.
+type VerificationPolicy string
+
+const (
+	// VerifyOff lets unverified users use every endpoint.
+	VerifyOff VerificationPolicy = "off"
+	// VerifyWrites blocks unverified users from anything but reads.
+	VerifyWrites VerificationPolicy = "writes"
+	// VerifyAll blocks unverified users from every authenticated endpoint.
+	VerifyAll VerificationPolicy = "all"
+)
+
+// UserVerified reports whether the user has verified their email. It is only
+// consulted when the token was issued before verification and is wired up by
+// main; while nil the token claim alone decides.
+var UserVerified func(userID primitive.ObjectID) (bool, error)
+
+// verificationPolicy reads EMAIL_VERIFICATION_POLICY, defaulting to off so
+// accounts created before verification was enforced keep working.
+func verificationPolicy() VerificationPolicy {
+	switch p := VerificationPolicy(strings.ToLower(os.Getenv("EMAIL_VERIFICATION_POLICY"))); p {
+	case VerifyWrites, VerifyAll:
+		return p
+	}
+	return VerifyOff
+}
+
+// AllowUnverified exempts a route from the verification policy. It must run
+// before AuthMiddleware, e.g. for logout or the resend endpoint.
+func AllowUnverified(c *fiber.Ctx) error {
+	c.Locals("allow_unverified", true)
+	return c.Next()
+}
+
+// requiresVerification reports whether the policy applies to this request.
+func requiresVerification(c *fiber.Ctx) bool {
+	if allowed, _ := c.Locals("allow_unverified").(bool); allowed {
+		return false
+	}
+	switch verificationPolicy() {
+	case VerifyAll:
+		return true
+	case VerifyWrites:
+		switch c.Method() {
+		case fiber.MethodGet, fiber.MethodHead, fiber.MethodOptions:
+			return false
+		}
+		return true
+	}
+	return false
+}
+
+// unverifiedBlocked reports whether the policy rejects this request of user,
+// whose token carried the given is_verified claim. Admins are never blocked.
+func unverifiedBlocked(c *fiber.Ctx, user *models.User, verified bool) (bool, error) {
+	if verified || user.IsAdmin || !requiresVerification(c) {
+		return false, nil
+	}
+	if UserVerified == nil {
+		return true, nil
+	}
+	ok, err := UserVerified(user.ID)
+	if err != nil {
+		return false, err
+	}
+	return !ok, nil
+}
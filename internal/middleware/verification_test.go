@@ -0,0 +1,22 @@
+package middleware
+
+import "testing"
+
+func TestVerificationPolicy(t *testing.T) {
+	tests := []struct {
+		env  string
+		want VerificationPolicy
+	}{
+		{"", VerifyOff},
+		{"unknown", VerifyOff},
+		{"off", VerifyOff},
+		{"WRITES", VerifyWrites},
+		{"all", VerifyAll},
+	}
+	for _, tt := range tests {
+		t.Setenv("EMAIL_VERIFICATION_POLICY", tt.env)
+		if got := verificationPolicy(); got != tt.want {
+			t.Errorf("EMAIL_VERIFICATION_POLICY=%q: got %q, want %q", tt.env, got, tt.want)
+		}
+	}
+}
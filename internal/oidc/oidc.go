@@ -0,0 +1,320 @@
+// Package oidc is a minimal OpenID Connect relying party: discovery, the
+// authorization code flow with PKCE (RFC 7636) and ID token verification
+// against the provider's JWKS. Only RSA signed ID tokens are supported.
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type Config struct {
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
+// Claims are the ID token claims Managify uses.
+type Claims struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+}
+
+type metadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+// Provider is safe for concurrent use.
+type Provider struct {
+	cfg    Config
+	meta   metadata
+	client *http.Client
+
+	mu   sync.RWMutex
+	keys map[string]*rsa.PublicKey
+}
+
+// NewProvider reads the discovery document of cfg.Issuer.
+func NewProvider(ctx context.Context, cfg Config) (*Provider, error) {
+	p := &Provider{cfg: cfg, client: &http.Client{Timeout: 10 * time.Second}}
+
+	wellKnown := strings.TrimSuffix(cfg.Issuer, "/") + "/.well-known/openid-configuration"
+	if err := p.getJSON(ctx, wellKnown, "", &p.meta); err != nil {
+		return nil, fmt.Errorf("oidc discovery failed: %w", err)
+	}
+	if strings.TrimSuffix(p.meta.Issuer, "/") != strings.TrimSuffix(cfg.Issuer, "/") {
+		return nil, fmt.Errorf("oidc discovery returned issuer %s, expected %s", p.meta.Issuer, cfg.Issuer)
+	}
+	if p.meta.AuthorizationEndpoint == "" || p.meta.TokenEndpoint == "" || p.meta.JWKSURI == "" {
+		return nil, fmt.Errorf("oidc discovery document is incomplete")
+	}
+	return p, nil
+}
+
+// Issuer returns the issuer as announced by the provider.
+func (p *Provider) Issuer() string {
+	return p.meta.Issuer
+}
+
+// RandomString returns a URL safe random string for state, nonce and the
+// PKCE code verifier.
+func RandomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge returns the S256 PKCE challenge of verifier.
+func CodeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
+// AuthCodeURL returns the URL the browser is sent to for login.
+func (p *Provider) AuthCodeURL(state, nonce, verifier string) string {
+	params := url.Values{}
+	params.Set("response_type", "code")
+	params.Set("client_id", p.cfg.ClientID)
+	params.Set("redirect_uri", p.cfg.RedirectURL)
+	params.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	params.Set("state", state)
+	params.Set("nonce", nonce)
+	params.Set("code_challenge", CodeChallenge(verifier))
+	params.Set("code_challenge_method", "S256")
+
+	sep := "?"
+	if strings.Contains(p.meta.AuthorizationEndpoint, "?") {
+		sep = "&"
+	}
+	return p.meta.AuthorizationEndpoint + sep + params.Encode()
+}
+
+// Exchange redeems an authorization code and returns the verified claims of
+// the ID token. Email claims missing from the ID token are read from the
+// userinfo endpoint.
+func (p *Provider) Exchange(ctx context.Context, code, verifier, nonce string) (*Claims, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("code_verifier", verifier)
+	form.Set("client_id", p.cfg.ClientID)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.meta.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if p.cfg.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		IDToken     string `json:"id_token"`
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	if err := p.doJSON(req, &token); err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	if token.Error != "" {
+		return nil, fmt.Errorf("token request failed: %s %s", token.Error, token.Description)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("token response has no id_token")
+	}
+
+	claims, err := p.VerifyIDToken(ctx, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.Email == "" && token.AccessToken != "" && p.meta.UserinfoEndpoint != "" {
+		var info struct {
+			Subject       string `json:"sub"`
+			Email         string `json:"email"`
+			EmailVerified any    `json:"email_verified"`
+			Name          string `json:"name"`
+		}
+		if err := p.getJSON(ctx, p.meta.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, fmt.Errorf("userinfo request failed: %w", err)
+		}
+		if info.Subject != claims.Subject {
+			return nil, fmt.Errorf("userinfo subject does not match the id token")
+		}
+		claims.Email = info.Email
+		claims.EmailVerified = boolClaim(info.EmailVerified)
+		if claims.Name == "" {
+			claims.Name = info.Name
+		}
+	}
+	return claims, nil
+}
+
+// VerifyIDToken checks the signature, issuer, audience, expiry and nonce of
+// an ID token.
+func (p *Provider) VerifyIDToken(ctx context.Context, raw, nonce string) (*Claims, error) {
+	token, err := jwt.Parse(raw, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return p.key(ctx, kid)
+	},
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512"}),
+		jwt.WithIssuer(p.meta.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(time.Minute),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("invalid id token: %w", err)
+	}
+
+	mc, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, fmt.Errorf("invalid id token claims")
+	}
+	if got, _ := mc["nonce"].(string); got != nonce {
+		return nil, fmt.Errorf("id token nonce does not match")
+	}
+
+	claims := &Claims{}
+	claims.Subject, _ = mc["sub"].(string)
+	claims.Email, _ = mc["email"].(string)
+	claims.Name, _ = mc["name"].(string)
+	claims.EmailVerified = boolClaim(mc["email_verified"])
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("id token has no subject")
+	}
+	return claims, nil
+}
+
+// key returns the signing key with the given id, refreshing the JWKS once
+// when it is unknown, e.g. after a key rotation.
+func (p *Provider) key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	p.mu.RLock()
+	key, ok := p.lookup(kid)
+	p.mu.RUnlock()
+	if ok {
+		return key, nil
+	}
+
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := p.getJSON(ctx, p.meta.JWKSURI, "", &set); err != nil {
+		return nil, fmt.Errorf("jwks request failed: %w", err)
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			continue
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			continue
+		}
+		keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	key, ok = p.lookup(kid)
+	p.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// lookup finds kid in the cached keys. Tokens without a kid are accepted
+// when the provider publishes a single key.
+func (p *Provider) lookup(kid string) (*rsa.PublicKey, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, k := range p.keys {
+			return k, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) getJSON(ctx context.Context, endpoint, bearer string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	return p.doJSON(req, v)
+}
+
+// doJSON decodes the response body into v. Error responses of the token
+// endpoint are JSON too, so 400 bodies are decoded as well.
+func (p *Provider) doJSON(req *http.Request, v any) error {
+	res, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusBadRequest {
+		return fmt.Errorf("%s returned %s", req.URL.Redacted(), res.Status)
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("%s returned invalid JSON: %w", req.URL.Redacted(), err)
+	}
+	return nil
+}
+
+// boolClaim accepts booleans and the string form some providers send.
+func boolClaim(v any) bool {
+	switch b := v.(type) {
+	case bool:
+		return b
+	case string:
+		return b == "true"
+	}
+	return false
+}
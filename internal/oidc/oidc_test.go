@@ -0,0 +1,289 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID = "managify"
+	testNonce    = "nonce-1"
+)
+
+// testIdP is an OpenID provider that issues an ID token for every code whose
+// verifier matches the PKCE challenge of the last authorization request.
+type testIdP struct {
+	*httptest.Server
+	t *testing.T
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PrivateKey
+	signKid   string
+	claims    jwt.MapClaims
+	challenge string
+	jwksHits  int
+}
+
+func newTestIdP(t *testing.T) *testIdP {
+	idp := &testIdP{t: t, keys: map[string]*rsa.PrivateKey{}}
+	idp.rotate("key-1")
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.URL,
+			"authorization_endpoint": idp.URL + "/authorize",
+			"token_endpoint":         idp.URL + "/token",
+			"jwks_uri":               idp.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+		idp.jwksHits++
+
+		keys := []map[string]string{}
+		for kid, key := range idp.keys {
+			keys = append(keys, map[string]string{
+				"kid": kid,
+				"kty": "RSA",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			})
+		}
+		json.NewEncoder(w).Encode(map[string]any{"keys": keys})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		idp.mu.Lock()
+		defer idp.mu.Unlock()
+
+		if r.FormValue("code") != "code-1" || CodeChallenge(r.FormValue("code_verifier")) != idp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"id_token": idp.sign()})
+	})
+	idp.Server = httptest.NewServer(mux)
+	t.Cleanup(idp.Close)
+
+	idp.claims = jwt.MapClaims{
+		"iss":            idp.URL,
+		"aud":            testClientID,
+		"sub":            "user-1",
+		"email":          "jane@example.com",
+		"email_verified": true,
+		"nonce":          testNonce,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+	}
+	return idp
+}
+
+// rotate adds a signing key and signs with it from now on.
+func (idp *testIdP) rotate(kid string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.keys[kid] = key
+	idp.signKid = kid
+}
+
+// sign returns an ID token of the current claims. Callers hold idp.mu.
+func (idp *testIdP) sign() string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+	token.Header["kid"] = idp.signKid
+	raw, err := token.SignedString(idp.keys[idp.signKid])
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	return raw
+}
+
+func (idp *testIdP) jwksRequests() int {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	return idp.jwksHits
+}
+
+func (idp *testIdP) set(name string, value any) {
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.claims[name] = value
+}
+
+// login starts a login and lets the provider record its PKCE challenge.
+func (idp *testIdP) login(p *Provider, verifier string) {
+	u, err := url.Parse(p.AuthCodeURL("state-1", testNonce, verifier))
+	if err != nil {
+		idp.t.Fatal(err)
+	}
+	if got := u.Query().Get("code_challenge_method"); got != "S256" {
+		idp.t.Fatalf("code_challenge_method = %q", got)
+	}
+	idp.mu.Lock()
+	defer idp.mu.Unlock()
+	idp.challenge = u.Query().Get("code_challenge")
+}
+
+func newTestProvider(t *testing.T, idp *testIdP) *Provider {
+	p, err := NewProvider(context.Background(), Config{
+		Issuer:      idp.URL,
+		ClientID:    testClientID,
+		RedirectURL: "http://localhost/sso/callback",
+		Scopes:      []string{"openid", "email"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestCodeChallengeRFC7636(t *testing.T) {
+	// RFC 7636 appendix B.
+	got := CodeChallenge("dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk")
+	if got != "E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM" {
+		t.Errorf("CodeChallenge = %s", got)
+	}
+}
+
+func TestExchange(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newTestProvider(t, idp)
+
+	idp.login(p, "verifier-1")
+	claims, err := p.Exchange(context.Background(), "code-1", "verifier-1", testNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Subject != "user-1" || claims.Email != "jane@example.com" || !claims.EmailVerified {
+		t.Errorf("claims = %+v", claims)
+	}
+}
+
+func TestExchangeRejectsWrongVerifier(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newTestProvider(t, idp)
+
+	idp.login(p, "verifier-1")
+	if _, err := p.Exchange(context.Background(), "code-1", "verifier-2", testNonce); err == nil {
+		t.Fatal("code was redeemed with another verifier")
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newTestProvider(t, idp)
+
+	idp.login(p, "verifier-1")
+	_, err := p.Exchange(context.Background(), "code-1", "verifier-1", "other-nonce")
+	if err == nil || !strings.Contains(err.Error(), "nonce") {
+		t.Fatalf("Exchange = %v, want nonce error", err)
+	}
+}
+
+func TestVerifyIDTokenRejectsWrongClaims(t *testing.T) {
+	tests := []struct {
+		name  string
+		claim string
+		value any
+	}{
+		{"audience", "aud", "another-client"},
+		{"issuer", "iss", "https://evil.example.com"},
+		{"expired", "exp", time.Now().Add(-time.Hour).Unix()},
+		{"subject", "sub", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idp := newTestIdP(t)
+			p := newTestProvider(t, idp)
+			idp.set(tt.claim, tt.value)
+
+			idp.mu.Lock()
+			raw := idp.sign()
+			idp.mu.Unlock()
+			if _, err := p.VerifyIDToken(context.Background(), raw, testNonce); err == nil {
+				t.Fatalf("token with wrong %s was accepted", tt.name)
+			}
+		})
+	}
+}
+
+func TestVerifyIDTokenRejectsForeignKey(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newTestProvider(t, idp)
+
+	// Signed with a key the provider does not publish, under a published kid.
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+	token.Header["kid"] = "key-1"
+	raw, err := token.SignedString(other)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.VerifyIDToken(context.Background(), raw, testNonce); err == nil {
+		t.Fatal("token signed with a foreign key was accepted")
+	}
+}
+
+func TestVerifyIDTokenRefreshesJWKSForUnknownKid(t *testing.T) {
+	idp := newTestIdP(t)
+	p := newTestProvider(t, idp)
+	ctx := context.Background()
+
+	verify := func() error {
+		idp.mu.Lock()
+		raw := idp.sign()
+		idp.mu.Unlock()
+		_, err := p.VerifyIDToken(ctx, raw, testNonce)
+		return err
+	}
+
+	if err := verify(); err != nil {
+		t.Fatal(err)
+	}
+	if err := verify(); err != nil {
+		t.Fatal(err)
+	}
+	if n := idp.jwksRequests(); n != 1 {
+		t.Fatalf("JWKS fetched %d times, want 1 while the key is cached", n)
+	}
+
+	idp.rotate("key-2")
+	if err := verify(); err != nil {
+		t.Fatalf("after key rotation: %v", err)
+	}
+	if n := idp.jwksRequests(); n != 2 {
+		t.Fatalf("JWKS fetched %d times, want a refresh for the new kid", n)
+	}
+
+	idp.mu.Lock()
+	idp.signKid = "key-3"
+	idp.keys["key-3"], _ = rsa.GenerateKey(rand.Reader, 2048)
+	raw := idp.sign()
+	delete(idp.keys, "key-3")
+	idp.mu.Unlock()
+	_, err := p.VerifyIDToken(ctx, raw, testNonce)
+	if err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Fatalf("unpublished kid: %v, want unknown signing key", err)
+	}
+}
@@ -0,0 +1,152 @@
+// Package realtime fans out project events to the clients watching a board.
+// Events are kept in memory only, so every API instance serves the clients
+// connected to it.
+package realtime
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var log = logrus.New()
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+}
+
+// Event types sent to subscribers.
+const (
+	IssueCreated  = "issue.created"
+	IssueUpdated  = "issue.updated"
+	IssueMoved    = "issue.moved"
+	IssueDeleted  = "issue.deleted"
+	StatusCreated = "status.created"
+	StatusDeleted = "status.deleted"
+	MemberJoined  = "member.joined"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is disconnected; the client then reconnects and reloads the board.
+const subscriberBuffer = 64
+
+// Event is a change in a project. Data is encoded as JSON.
+type Event struct {
+	ID        uint64             `json:"id"`
+	Type      string             `json:"type"`
+	ProjectID primitive.ObjectID `json:"project_id"`
+	ActorID   primitive.ObjectID `json:"actor_id,omitempty"`
+	Data      any                `json:"data"`
+	Time      time.Time          `json:"time"`
+}
+
+// Subscription receives the events of one project until it is closed.
+type Subscription struct {
+	ProjectID primitive.ObjectID
+	UserID    primitive.ObjectID
+
+	hub    *Hub
+	events chan Event
+	once   sync.Once
+}
+
+// Events is closed when the subscription ends, either through Close or
+// because the subscriber fell too far behind.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.hub.remove(s)
+}
+
+// Hub tracks the subscribers of every project.
+type Hub struct {
+	mu       sync.RWMutex
+	projects map[primitive.ObjectID]map[*Subscription]struct{}
+	nextID   atomic.Uint64
+}
+
+func NewHub() *Hub {
+	return &Hub{projects: map[primitive.ObjectID]map[*Subscription]struct{}{}}
+}
+
+var defaultHub = NewHub()
+
+// Default returns the hub shared by the services and the stream handler.
+func Default() *Hub {
+	return defaultHub
+}
+
+// Subscribe starts receiving the events of a project.
+func (h *Hub) Subscribe(projectID, userID primitive.ObjectID) *Subscription {
+	sub := &Subscription{
+		ProjectID: projectID,
+		UserID:    userID,
+		hub:       h,
+		events:    make(chan Event, subscriberBuffer),
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.projects[projectID] == nil {
+		h.projects[projectID] = map[*Subscription]struct{}{}
+	}
+	h.projects[projectID][sub] = struct{}{}
+	return sub
+}
+
+func (h *Hub) remove(sub *Subscription) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	subs := h.projects[sub.ProjectID]
+	if _, ok := subs[sub]; ok {
+		delete(subs, sub)
+		if len(subs) == 0 {
+			delete(h.projects, sub.ProjectID)
+		}
+	}
+	sub.once.Do(func() { close(sub.events) })
+}
+
+// Publish sends an event to every subscriber of the project. It never
+// blocks; subscribers whose buffer is full are dropped.
+func (h *Hub) Publish(projectID, actorID primitive.ObjectID, eventType string, data any) {
+	event := Event{
+		ID:        h.nextID.Add(1),
+		Type:      eventType,
+		ProjectID: projectID,
+		ActorID:   actorID,
+		Data:      data,
+		Time:      time.Now(),
+	}
+
+	var slow []*Subscription
+	h.mu.RLock()
+	for sub := range h.projects[projectID] {
+		select {
+		case sub.events <- event:
+		default:
+			slow = append(slow, sub)
+		}
+	}
+	h.mu.RUnlock()
+
+	for _, sub := range slow {
+		log.Warnf("Dropping slow realtime subscriber %s of project %s", sub.UserID.Hex(), projectID.Hex())
+		sub.Close()
+	}
+}
+
+// Publish sends an event through the default hub.
+func Publish(projectID, actorID primitive.ObjectID, eventType string, data any) {
+	defaultHub.Publish(projectID, actorID, eventType, data)
+}
@@ -0,0 +1,58 @@
+package realtime
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// keepAliveInterval keeps proxies from closing idle streams.
+const keepAliveInterval = 25 * time.Second
+
+// Stream writes the subscription as Server-Sent Events until the client goes
+// away, the subscription is dropped or until passes. A zero until streams
+// without a deadline. When until passes an "expired" event tells the client
+// to reconnect with a fresh access token.
+func (s *Subscription) Stream(w *bufio.Writer, until time.Time) {
+	defer s.Close()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+
+	var expired <-chan time.Time
+	if !until.IsZero() {
+		timer := time.NewTimer(time.Until(until))
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	fmt.Fprintf(w, "retry: 3000\nevent: ready\ndata: {\"project_id\":%q}\n\n", s.ProjectID.Hex())
+	if w.Flush() != nil {
+		return
+	}
+
+	for {
+		select {
+		case event, ok := <-s.events:
+			if !ok {
+				return
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				log.WithError(err).Errorf("failed to encode realtime event %s", event.Type)
+				continue
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+		case <-expired:
+			fmt.Fprint(w, "event: expired\ndata: {}\n\n")
+			w.Flush()
+			return
+		}
+		if w.Flush() != nil {
+			return
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package router
 
 import (
+	"managify/internal/guard"
 	"managify/internal/handler"
 	"managify/internal/middleware"
 	"managify/internal/router/routes"
+	"managify/models"
 	"os"
 
 	"managify/internal/validation"
@@ -19,7 +21,11 @@ func Routers(app *fiber.App) {
 	RouterInvite(app)
 	RouterRole(app)
 	RouterIssue(app)
+	RouterBoard(app)
 	RouterStatus(app)
+	RouterSearch(app)
+	RouterNotification(app)
+	RouterRealtime(app)
 	RouterLogger(app)
 	RouterSwagger(app)
 	RouterMetrics(app)
@@ -35,8 +41,33 @@ func Routers(app *fiber.App) {
 func RouterUser(app *fiber.App) {
 	api := app.Group(routes.UserBase)
 	api.Get(routes.UserVerifyEmail, handler.VerifyEmailHandler)
+	api.Post(routes.UserResendVerification, middleware.AllowUnverified, middleware.AuthMiddleware, handler.ResendVerificationHandler)
 	api.Post(routes.UserRegister, validation.CreateRegisterValidator, handler.CreateRegisterHandler)
 	api.Post(routes.UserAuth, validation.AuthValidator, handler.LoginHandler)
+	api.Post(routes.UserAuthTwoFactor, handler.TwoFactorLoginHandler)
+	api.Get(routes.UserAuthOIDC, handler.StartOIDCLoginHandler)
+	api.Post(routes.UserAuthOIDCCallback, handler.OIDCCallbackHandler)
+	api.Post(routes.UserRefresh, handler.RefreshTokenHandler)
+	api.Post(routes.UserLogout, middleware.AllowUnverified, middleware.AuthMiddleware, middleware.SessionOnly, handler.LogoutHandler)
+	api.Post(routes.UserLogoutAll, middleware.AllowUnverified, middleware.AuthMiddleware, middleware.SessionOnly, handler.LogoutAllHandler)
+	api.Post(routes.UserPasswordResetRequest, validation.PasswordResetRequestValidator, handler.RequestPasswordResetHandler)
+	api.Post(routes.UserPasswordResetConfirm, validation.PasswordResetConfirmValidator, handler.ConfirmPasswordResetHandler)
+	api.Get(routes.UserConfirmEmail, handler.ConfirmEmailChangeHandler)
+	api.Get(routes.UserMe, middleware.AuthMiddleware, handler.GetProfileHandler)
+	api.Put(routes.UserMe, middleware.AuthMiddleware, middleware.SessionOnly, validation.ProfileUpdateValidator, handler.UpdateProfileHandler)
+	api.Delete(routes.UserMe, middleware.AllowUnverified, middleware.AuthMiddleware, middleware.SessionOnly, validation.AccountDeleteValidator, handler.DeleteAccountHandler)
+	api.Put(routes.UserMePassword, middleware.AllowUnverified, middleware.AuthMiddleware, middleware.SessionOnly, validation.PasswordChangeValidator, handler.ChangePasswordHandler)
+	api.Post(routes.UserMeEmail, middleware.AllowUnverified, middleware.AuthMiddleware, middleware.SessionOnly, validation.EmailChangeValidator, handler.RequestEmailChangeHandler)
+	api.Get(routes.UserMeNotifications, middleware.AuthMiddleware, handler.GetNotificationPreferencesHandler)
+	api.Put(routes.UserMeNotifications, middleware.AuthMiddleware, middleware.SessionOnly, handler.UpdateNotificationPreferencesHandler)
+	api.Post(routes.UserTwoFactorSetup, middleware.AuthMiddleware, middleware.SessionOnly, handler.SetupTwoFactorHandler)
+	api.Post(routes.UserTwoFactorEnable, middleware.AuthMiddleware, middleware.SessionOnly, handler.EnableTwoFactorHandler)
+	api.Post(routes.UserTwoFactorDisable, middleware.AuthMiddleware, middleware.SessionOnly, handler.DisableTwoFactorHandler)
+	api.Post(routes.UserTwoFactorRecovery, middleware.AuthMiddleware, middleware.SessionOnly, handler.RegenerateRecoveryCodesHandler)
+	api.Get(routes.UserAPIKeys, middleware.AuthMiddleware, middleware.SessionOnly, handler.GetAPIKeysHandler)
+	api.Post(routes.UserAPIKeys, middleware.AuthMiddleware, middleware.SessionOnly, handler.CreateAPIKeyHandler)
+	api.Delete(routes.UserAPIKeyRevoke, middleware.AuthMiddleware, middleware.SessionOnly, handler.RevokeAPIKeyHandler)
+	// Must stay after the fixed paths above, /:id would match them.
 	api.Get(routes.UserGetById, middleware.AuthMiddleware, handler.GetUserByIdHandler)
 
 }
@@ -49,47 +80,122 @@ func RouterAdmin(app *fiber.App) {
 	api.Get(routes.AdminGetProjects, handler.GetProjectsHandler)
 	api.Get(routes.AdminGetRoles, handler.GetRolesHandler)
 	api.Delete(routes.AdminDelete, handler.DeleteUserById)
+	api.Post(routes.AdminTemplateCreate, handler.CreateProjectTemplateHandler)
+	api.Delete(routes.AdminTemplateDelete, handler.DeleteProjectTemplateHandler)
+	api.Post(routes.AdminUnlockUser, handler.UnlockUserHandler)
+	api.Get(routes.AdminAuditLogs, handler.GetAuditLogsHandler)
+	api.Get(routes.AdminEmails, handler.GetEmailsHandler)
+	api.Post(routes.AdminEmailRetry, handler.RetryEmailHandler)
 }
 
 func RouterProject(app *fiber.App) {
 	api := app.Group(routes.ProjectBase, middleware.AuthMiddleware)
 
 	api.Post(routes.ProjectCreate, handler.CreateProjectHandler)
-	api.Delete(routes.ProjectDelete, handler.DeleteProjectHandler)
-	api.Get(routes.ProjectGet, handler.GetProjectHandler)
-	api.Delete(routes.ProjectMemberDelete, handler.DeleteMemberFromProjectByIdHandler)
+	api.Delete(routes.ProjectDelete, guard.RequireIncludingDeleted(models.PermProjectDelete, guard.FromParam("id")), handler.DeleteProjectHandler)
+	api.Get(routes.ProjectGet, guard.Require(models.PermProjectView, guard.FromParam("id")), handler.GetProjectHandler)
+	api.Delete(routes.ProjectMemberDelete, guard.Require(models.PermMemberRemove, guard.FromParam("id")), handler.DeleteMemberFromProjectByIdHandler)
+	api.Patch(routes.ProjectUpdate, guard.Require(models.PermProjectUpdate, guard.FromParam("id")), validation.UpdateProjectValidator, handler.UpdateProjectHandler)
+	api.Put(routes.ProjectStatus, guard.Require(models.PermProjectUpdate, guard.FromParam("id")), handler.UpdateProjectStatusHandler)
+	api.Put(routes.ProjectRestore, guard.RequireIncludingDeleted(models.PermProjectDelete, guard.FromParam("id")), handler.RestoreProjectHandler)
+	api.Get(routes.ProjectDeletedGet, handler.GetDeletedProjectsHandler)
+	api.Get(routes.ProjectTemplatesGet, handler.GetProjectTemplatesHandler)
+
+	webhookGuard := guard.Require(models.PermWebhookManage, guard.FromParam("id"))
+	api.Get(routes.ProjectWebhooks, webhookGuard, handler.GetWebhooksHandler)
+	api.Post(routes.ProjectWebhooks, webhookGuard, handler.CreateWebhookHandler)
+	api.Patch(routes.ProjectWebhook, webhookGuard, handler.UpdateWebhookHandler)
+	api.Delete(routes.ProjectWebhook, webhookGuard, handler.DeleteWebhookHandler)
+	api.Get(routes.ProjectWebhookDeliveries, webhookGuard, handler.GetWebhookDeliveriesHandler)
+	api.Post(routes.ProjectWebhookRedeliver, webhookGuard, handler.RedeliverWebhookHandler)
 }
 
 func RouterInvite(app *fiber.App) {
 	api := app.Group(routes.InviteBase, middleware.AuthMiddleware)
 
 	api.Get(routes.InviteGetById, handler.GetInviteHandlerById)
-	api.Post(routes.InviteCreate, handler.CreateProjectInviteHandler)
+	api.Post(routes.InviteCreate, guard.Require(models.PermInviteSend, guard.FromBody()), handler.CreateProjectInviteHandler)
 	api.Put(routes.InviteRespond, handler.RespondProjectInviteHandler)
 }
 
 func RouterRole(app *fiber.App) {
 	api := app.Group(routes.RoleBase, middleware.AuthMiddleware)
 
-	api.Post(routes.RoleCreate, handler.CreateRoleHandler)
-	api.Delete(routes.RoleDelete, handler.DeleteRoleHandler)
+	api.Post(routes.RoleCreate, guard.Require(models.PermRoleManage, guard.FromBody()), validation.CreateRoleValidator, handler.CreateRoleHandler)
+	api.Delete(routes.RoleDelete, guard.Require(models.PermRoleManage, guard.FromParam("project")), handler.DeleteRoleHandler)
+	api.Get(routes.RoleMembersGet, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetProjectMembersHandler)
+	api.Put(routes.RoleMemberSet, guard.Require(models.PermRoleManage, guard.FromParam("projectID")), handler.UpdateMemberRoleHandler)
+
+	api.Post(routes.RoleDefinitionCreate, guard.Require(models.PermRoleManage, guard.FromParam("projectID")), validation.RoleDefinitionValidator, handler.CreateRoleDefinitionHandler)
+	api.Get(routes.RoleDefinitionsGet, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetRoleDefinitionsHandler)
+	api.Put(routes.RoleDefinitionUpdate, guard.Require(models.PermRoleManage, guard.FromParam("projectID")), validation.RoleDefinitionValidator, handler.UpdateRoleDefinitionHandler)
+	api.Delete(routes.RoleDefinitionDelete, guard.Require(models.PermRoleManage, guard.FromParam("projectID")), handler.DeleteRoleDefinitionHandler)
 }
 
 func RouterStatus(app *fiber.App) {
 	api := app.Group(routes.StatusBase, middleware.AuthMiddleware)
 
-	api.Post(routes.StatusCreate, validation.CreateStatusValidator, handler.CreateStatusHandler)
-	api.Delete(routes.StatusDelete, handler.DeleteStatusHandler)
+	api.Post(routes.StatusCreate, validation.CreateStatusValidator, guard.Require(models.PermStatusManage, guard.FromBody()), handler.CreateStatusHandler)
+	api.Delete(routes.StatusDelete, guard.Require(models.PermStatusManage, guard.FromParam("project")), handler.DeleteStatusHandler)
+	api.Get(routes.StatusWorkflowGet, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetWorkflowHandler)
+	api.Put(routes.StatusWorkflowSet, guard.Require(models.PermStatusManage, guard.FromParam("projectID")), handler.SetWorkflowHandler)
+}
+
+func RouterBoard(app *fiber.App) {
+	api := app.Group(routes.BoardBase, middleware.AuthMiddleware)
+
+	api.Get(routes.BoardGet, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetBoardHandler)
+	api.Put(routes.BoardMoveIssue, guard.Require(models.PermIssueUpdate, guard.FromIssueParam("issueID")), handler.MoveIssueHandler)
+	api.Put(routes.BoardReorderColumns, guard.Require(models.PermStatusManage, guard.FromParam("projectID")), handler.ReorderColumnsHandler)
 }
 
 func RouterIssue(app *fiber.App) {
 	api := app.Group(routes.IssueBase, middleware.AuthMiddleware)
 
-	api.Post(routes.IssueCreate, handler.CreateIssueHandler)
-	api.Delete(routes.IssueDelete, handler.DeleteIssueHandler)
-	api.Get(routes.IssuesGet, handler.GetIssuesByStatusHandler)
-	api.Put(routes.IssueUpdate, handler.UpdateIssueStatusHandler)
-	api.Get(routes.IssueGetOnDue, handler.GetOncomingIssuesHandler)
+	api.Post(routes.IssueCreate, guard.Require(models.PermIssueCreate, guard.FromBody()), handler.CreateIssueHandler)
+	api.Delete(routes.IssueDelete, guard.Require(models.PermIssueDelete, guard.FromIssueParam("id")), handler.DeleteIssueHandler)
+	api.Get(routes.IssuesGet, guard.Require(models.PermProjectView, guard.FromStatusParam("statusID")), handler.GetIssuesByStatusHandler)
+	api.Put(routes.IssueUpdate, guard.Require(models.PermIssueUpdate, guard.FromIssueParam("issueID")), handler.UpdateIssueStatusHandler)
+	api.Get(routes.IssueGetOnDue, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetOncomingIssuesHandler)
+	api.Patch(routes.IssuePatch, guard.Require(models.PermIssueUpdate, guard.FromIssueParam("issueID")), validation.UpdateIssueValidator, handler.UpdateIssueHandler)
+	api.Get(routes.IssueHistory, guard.Require(models.PermProjectView, guard.FromIssueParam("issueID")), handler.GetIssueHistoryHandler)
+	api.Get(routes.IssueQuery, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.QueryIssuesHandler)
+
+	api.Put(routes.IssueAssign, guard.Require(models.PermIssueAssign, guard.FromIssueParam("issueID")), handler.AssignIssueHandler)
+	api.Put(routes.IssueReassign, guard.Require(models.PermIssueAssign, guard.FromIssueParam("issueID")), handler.ReassignIssueHandler)
+	api.Put(routes.IssueUnassign, guard.Require(models.PermIssueAssign, guard.FromIssueParam("issueID")), handler.UnassignIssueHandler)
+	api.Get(routes.IssuesAssigned, handler.GetAssignedIssuesHandler)
+	api.Get(routes.IssueWorkload, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.GetWorkloadHandler)
+	api.Put(routes.IssueWatch, guard.Require(models.PermProjectView, guard.FromIssueParam("issueID")), handler.WatchIssueHandler)
+	api.Delete(routes.IssueWatch, guard.Require(models.PermProjectView, guard.FromIssueParam("issueID")), handler.UnwatchIssueHandler)
+
+	api.Post(routes.IssueCommentCreate, guard.Require(models.PermCommentCreate, guard.FromIssueParam("issueID")), validation.CommentValidator, handler.CreateCommentHandler)
+	api.Post(routes.IssueCommentReply, guard.Require(models.PermCommentCreate, guard.FromIssueParam("issueID")), validation.CommentValidator, handler.ReplyCommentHandler)
+	api.Get(routes.IssueCommentsGet, guard.Require(models.PermProjectView, guard.FromIssueParam("issueID")), handler.GetCommentsHandler)
+	api.Put(routes.IssueCommentUpdate, guard.Require(models.PermCommentCreate, guard.FromCommentParam("commentID")), validation.CommentValidator, handler.UpdateCommentHandler)
+	api.Delete(routes.IssueCommentDelete, guard.Require(models.PermCommentCreate, guard.FromCommentParam("commentID")), handler.DeleteCommentHandler)
+}
+
+func RouterSearch(app *fiber.App) {
+	api := app.Group(routes.SearchBase, middleware.AuthMiddleware)
+
+	api.Get(routes.SearchGet, handler.SearchHandler)
+}
+
+func RouterNotification(app *fiber.App) {
+	api := app.Group(routes.NotificationBase, middleware.AuthMiddleware)
+
+	api.Get(routes.NotificationsGet, handler.GetNotificationsHandler)
+	api.Get(routes.NotificationUnreadCount, handler.GetUnreadCountHandler)
+	api.Put(routes.NotificationReadAll, handler.MarkAllNotificationsReadHandler)
+	api.Put(routes.NotificationRead, handler.MarkNotificationReadHandler)
+	api.Put(routes.NotificationUnread, handler.MarkNotificationUnreadHandler)
+}
+
+func RouterRealtime(app *fiber.App) {
+	api := app.Group(routes.RealtimeBase, middleware.QueryToken, middleware.AuthMiddleware)
+
+	api.Get(routes.RealtimeProject, guard.Require(models.PermProjectView, guard.FromParam("projectID")), handler.ProjectEventsHandler)
 }
 
 func RouterLogger(app *fiber.App) {
@@ -9,7 +9,32 @@ const (
 	UserAuth     = "/auth"
 	UserGetById  = "/:id"
 
-	UserVerifyEmail = "/verify-email"
+	UserVerifyEmail        = "/verify-email"
+	UserResendVerification = "/resend-verification"
+
+	UserRefresh   = "/refresh"
+	UserLogout    = "/logout"
+	UserLogoutAll = "/logout-all"
+
+	UserPasswordResetRequest = "/password-reset/request"
+	UserPasswordResetConfirm = "/password-reset/confirm"
+
+	UserMe              = "/me"
+	UserMePassword      = "/me/password"
+	UserMeEmail         = "/me/email"
+	UserMeNotifications = "/me/notifications"
+	UserConfirmEmail    = "/confirm-email"
+
+	UserAuthTwoFactor     = "/auth/2fa"
+	UserAuthOIDC          = "/auth/oidc"
+	UserAuthOIDCCallback  = "/auth/oidc/callback"
+	UserTwoFactorSetup    = "/me/2fa/setup"
+	UserTwoFactorEnable   = "/me/2fa/enable"
+	UserTwoFactorDisable  = "/me/2fa/disable"
+	UserTwoFactorRecovery = "/me/2fa/recovery-codes"
+
+	UserAPIKeys      = "/me/api-keys"
+	UserAPIKeyRevoke = "/me/api-keys/:keyID"
 
 	// Admin endpoints
 	AdminBase        = version + "/admin"
@@ -19,13 +44,32 @@ const (
 	AdminGetProjects = "/get-projects"
 	AdminGetRoles    = "/get-roles"
 
+	AdminTemplateCreate = "/create-template"
+	AdminTemplateDelete = "/delete-template/:id"
+
+	AdminUnlockUser = "/unlock-user/:id"
+	AdminAuditLogs  = "/audit-logs"
+	AdminEmails     = "/emails"
+	AdminEmailRetry = "/emails/:id/retry"
+
 	// Project endpoints
 
 	ProjectBase         = version + "/project"
 	ProjectCreate       = "/create-project"
 	ProjectDelete       = "/delete-project/:id"
 	ProjectGet          = "/projects/:id"
-	ProjectMemberDelete = "/projects/member/:memberId"
+	ProjectMemberDelete = "/projects/:id/member/:memberId"
+	ProjectUpdate       = "/update-project/:id"
+	ProjectStatus       = "/project-status/:id"
+	ProjectRestore      = "/restore-project/:id"
+	ProjectDeletedGet   = "/deleted-projects"
+	ProjectTemplatesGet = "/templates"
+
+	// Project webhook endpoints
+	ProjectWebhooks          = "/projects/:id/webhooks"
+	ProjectWebhook           = "/projects/:id/webhooks/:webhookID"
+	ProjectWebhookDeliveries = "/projects/:id/webhooks/:webhookID/deliveries"
+	ProjectWebhookRedeliver  = "/projects/:id/webhooks/:webhookID/deliveries/:deliveryID/redeliver"
 
 	// Project invite endpoints
 
@@ -36,15 +80,30 @@ const (
 
 	// Project role endpoints
 
-	RoleBase   = version + "/role"
-	RoleCreate = "/create-role"
-	RoleDelete = "/delete-role/:id/:project"
+	RoleBase             = version + "/role"
+	RoleCreate           = "/create-role"
+	RoleDelete           = "/delete-role/:id/:project"
+	RoleMembersGet       = "/members/:projectID"
+	RoleMemberSet        = "/member-role/:projectID/:userID"
+	RoleDefinitionCreate = "/create-definition/:projectID"
+	RoleDefinitionsGet   = "/definitions/:projectID"
+	RoleDefinitionUpdate = "/update-definition/:projectID/:definitionID"
+	RoleDefinitionDelete = "/delete-definition/:projectID/:definitionID"
 
 	// Project status endpoints
 
-	StatusBase   = version + "/status"
-	StatusCreate = "/create-status"
-	StatusDelete = "/delete-status/:id/:project"
+	StatusBase        = version + "/status"
+	StatusCreate      = "/create-status"
+	StatusDelete      = "/delete-status/:id/:project"
+	StatusWorkflowGet = "/workflow/:projectID"
+	StatusWorkflowSet = "/workflow/:projectID"
+
+	// Project board endpoints
+
+	BoardBase           = version + "/board"
+	BoardGet            = "/:projectID"
+	BoardMoveIssue      = "/move-issue/:issueID"
+	BoardReorderColumns = "/reorder-columns/:projectID"
 
 	// Project issue endpoints
 
@@ -54,6 +113,42 @@ const (
 	IssuesGet     = "/get/:statusID"
 	IssueUpdate   = "/update-status/:issueID/:statusID"
 	IssueGetOnDue = "/due-today/:projectID"
+	IssuePatch    = "/update-issue/:issueID"
+	IssueHistory  = "/history/:issueID"
+	IssueQuery    = "/query/:projectID"
+
+	// Issue assignment endpoints
+
+	IssueAssign    = "/assign/:issueID/:userID"
+	IssueReassign  = "/reassign/:issueID/:userID"
+	IssueUnassign  = "/unassign/:issueID"
+	IssuesAssigned = "/assigned"
+	IssueWorkload  = "/workload/:projectID"
+	IssueWatch     = "/watch/:issueID"
+
+	// Issue comment endpoints
+
+	IssueCommentCreate = "/comment/:issueID"
+	IssueCommentReply  = "/comment/:issueID/reply/:commentID"
+	IssueCommentsGet   = "/comments/:issueID"
+	IssueCommentUpdate = "/update-comment/:commentID"
+	IssueCommentDelete = "/delete-comment/:commentID"
+
+	// Search endpoint
+	SearchBase = version + "/search"
+	SearchGet  = "/"
+
+	// Notification inbox endpoints
+	NotificationBase        = version + "/notifications"
+	NotificationsGet        = "/"
+	NotificationUnreadCount = "/unread-count"
+	NotificationReadAll     = "/read-all"
+	NotificationRead        = "/:id/read"
+	NotificationUnread      = "/:id/unread"
+
+	// Realtime endpoint
+	RealtimeBase    = version + "/realtime"
+	RealtimeProject = "/projects/:projectID"
 
 	// Log endpoint
 	LoggerBase = version + "/logger"
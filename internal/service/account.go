@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/internal/mailer"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// emailChangeTTL is how long a requested email change can be confirmed.
+const emailChangeTTL = 24 * time.Hour
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+// UpdateProfile changes the full name of the user. Names are unique, like at
+// registration.
+func (s *UserService) UpdateProfile(userID primitive.ObjectID, req *request.ProfileUpdateRequest) (*models.User, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	name := strings.TrimSpace(req.FullName)
+	count, err := collection.CountDocuments(ctx, bson.M{"full_name": name, "_id": bson.M{"$ne": userID}})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("name already exists")
+	}
+
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"full_name": name}}); err != nil {
+		log.WithError(err).Error("failed to update profile")
+		return nil, err
+	}
+
+	return s.GetUserById(userID.Hex())
+}
+
+// ChangePassword replaces the password after checking the current one. Every
+// other session is logged out; the returned tokens keep the caller signed in.
+func (s *UserService) ChangePassword(userID primitive.ObjectID, req *request.PasswordChangeRequest, info SessionInfo) (*TokenPair, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.checkPassword(ctx, userID, req.CurrentPassword)
+	if err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := s.EncryptPassword([]byte(req.NewPassword))
+	if err != nil {
+		log.Errorf("Password encryption failed: %v", err)
+		return nil, err
+	}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"password": string(hashedPassword)}}); err != nil {
+		log.WithError(err).Error("failed to update password")
+		return nil, err
+	}
+
+	if err := GetAuthService().LogoutAll(userID); err != nil {
+		return nil, err
+	}
+
+	accessToken, err := s.CreateToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token")
+	}
+	refreshToken, err := GetAuthService().IssueRefreshToken(userID, info)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token")
+	}
+
+	log.Infof("Password changed for user %s", userID.Hex())
+	return &TokenPair{AccessToken: accessToken, RefreshToken: refreshToken}, nil
+}
+
+// RequestEmailChange sends a confirmation link to the new address. The
+// account keeps its current email until the link is used.
+func (s *UserService) RequestEmailChange(userID primitive.ObjectID, req *request.EmailChangeRequest) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.checkPassword(ctx, userID, req.CurrentPassword)
+	if err != nil {
+		return err
+	}
+
+	email := strings.TrimSpace(req.Email)
+	if email == user.Email {
+		return fmt.Errorf("this is already your email")
+	}
+	count, err := collection.CountDocuments(ctx, bson.M{"email": email})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("email already exists")
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		return err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"pending_email":           email,
+		"email_change_token":      hashToken(token),
+		"email_change_expires_at": time.Now().Add(emailChangeTTL),
+	}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": userID}, update); err != nil {
+		log.WithError(err).Error("failed to store email change")
+		return err
+	}
+
+	if err := sendEmailChangeEmail(ctx, user.FullName, email, token); err != nil {
+		log.WithError(err).Errorf("failed to queue email change confirmation to %s", email)
+		return err
+	}
+
+	return nil
+}
+
+// ConfirmEmailChange switches the account to the pending address of the
+// token. The new address counts as verified.
+func (s *UserService) ConfirmEmailChange(token string) (*models.User, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"email_change_token":      hashToken(token),
+		"email_change_expires_at": bson.M{"$gt": time.Now()},
+	}
+	var user models.User
+	if err := collection.FindOne(ctx, filter).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("confirmation link is invalid or has expired")
+		}
+		return nil, err
+	}
+
+	count, err := collection.CountDocuments(ctx, bson.M{"email": user.PendingEmail})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("email already exists")
+	}
+
+	update := bson.M{
+		"$set": bson.M{"email": user.PendingEmail, "isverified": true},
+		"$unset": bson.M{
+			"pending_email": "", "email_change_token": "", "email_change_expires_at": "",
+			"verificationtoken": "", "verification_expires_at": "",
+		},
+	}
+	res, err := collection.UpdateOne(ctx, bson.M{"_id": user.ID, "email_change_token": user.EmailChangeToken}, update)
+	if err != nil {
+		log.WithError(err).Error("failed to confirm email change")
+		return nil, err
+	}
+	if res.ModifiedCount == 0 {
+		return nil, fmt.Errorf("confirmation link is invalid or has expired")
+	}
+
+	log.Infof("Email changed for user %s", user.ID.Hex())
+	return s.GetUserById(user.ID.Hex())
+}
+
+// DeleteAccount removes the user after checking their password. Owned projects
+// listed in the request are handed over to the given member, every other owned
+// project is deleted permanently. The user is removed from every team.
+func (s *UserService) DeleteAccount(userID primitive.ObjectID, req *request.AccountDeleteRequest) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	user, err := s.checkPassword(ctx, userID, req.Password)
+	if err != nil {
+		return err
+	}
+
+	ps := GetProjectService()
+	projectsColl := database.DB.Collection(ps.Collection)
+
+	var owned []*models.Project
+	cursor, err := projectsColl.Find(ctx, bson.M{"owner_id": userID})
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &owned); err != nil {
+		return err
+	}
+
+	transfers, err := parseTransfers(owned, userID, req.Transfers)
+	if err != nil {
+		return err
+	}
+
+	for _, project := range owned {
+		if newOwnerID, ok := transfers[project.ID]; ok {
+			if err := ps.transferOwnership(ctx, project, newOwnerID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := ps.purgeProject(project); err != nil {
+			return err
+		}
+	}
+
+	var teams []*models.Project
+	cursor, err = projectsColl.Find(ctx, bson.M{"team": userID})
+	if err != nil {
+		return err
+	}
+	if err := cursor.All(ctx, &teams); err != nil {
+		return err
+	}
+
+	db := database.DB
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := projectsColl.UpdateMany(ctx, bson.M{"team": userID}, bson.M{"$pull": bson.M{"team": userID}}); err != nil {
+			return fmt.Errorf("failed to leave project teams: %w", err)
+		}
+		if _, err := db.Collection(GetIssueService().Collection).UpdateMany(ctx,
+			bson.M{"assignee_id": userID},
+			bson.M{"$unset": bson.M{"assignee_id": ""}},
+		); err != nil {
+			return fmt.Errorf("failed to unassign issues: %w", err)
+		}
+		if _, err := db.Collection(GetIssueService().Collection).UpdateMany(ctx,
+			bson.M{"watchers": userID},
+			bson.M{"$pull": bson.M{"watchers": userID}},
+		); err != nil {
+			return fmt.Errorf("failed to unwatch issues: %w", err)
+		}
+
+		byUser := bson.M{"user_id": userID}
+		deletes := []struct {
+			collection *mongo.Collection
+			filter     bson.M
+		}{
+			{db.Collection(GetRoleService().Collection), byUser},
+			{db.Collection(GetSubscriptionService().Collection), byUser},
+			{db.Collection(GetAuthService().Collection), byUser},
+			{db.Collection(GetPasswordResetService().Collection), byUser},
+			{db.Collection(GetTwoFactorService().Collection), byUser},
+			{db.Collection(GetAPIKeyService().Collection), byUser},
+			{db.Collection(GetNotificationService().Collection), byUser},
+			{db.Collection(GetInboxService().Collection), byUser},
+			{db.Collection("project_invites"), bson.M{"$or": []bson.M{{"sender_id": userID}, {"receiver_id": userID}}}},
+			{db.Collection(s.Collection), bson.M{"_id": userID}},
+		}
+		for _, d := range deletes {
+			if _, err := d.collection.DeleteMany(ctx, d.filter); err != nil {
+				return fmt.Errorf("failed to delete from %s: %w", d.collection.Name(), err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to delete account")
+		return err
+	}
+
+	for _, project := range teams {
+		projectLog := models.ProjectLog{
+			ID:        primitive.NewObjectID(),
+			ProjectID: project.ID.Hex(),
+			UserID:    userID.Hex(),
+			Message:   "Member has deleted their account -> " + user.FullName,
+			Timestamp: time.Now(),
+		}
+		if err := GetLogService().CreateLog(&projectLog); err != nil {
+			log.WithError(err).Error("failed to log account deletion")
+		}
+	}
+
+	log.Infof("Account deleted: %s", userID.Hex())
+	return nil
+}
+
+// transferOwnership makes newOwnerID, a member of the project, its owner. The
+// previous owner keeps no role in the project.
+func (s *ProjectService) transferOwnership(ctx context.Context, project *models.Project, newOwnerID primitive.ObjectID) error {
+	if err := reserveProjectSlot(ctx, newOwnerID); err != nil {
+		return fmt.Errorf("cannot transfer %s: %w", project.Name, err)
+	}
+
+	db := database.DB
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		res, err := db.Collection(s.Collection).UpdateOne(ctx,
+			bson.M{"_id": project.ID, "owner_id": project.OwnerID},
+			bson.M{"$set": bson.M{"owner_id": newOwnerID}, "$pull": bson.M{"team": newOwnerID}},
+		)
+		if err != nil {
+			return err
+		}
+		if res.ModifiedCount == 0 {
+			return fmt.Errorf("project %s changed owner concurrently", project.Name)
+		}
+		if _, err := db.Collection(GetRoleService().Collection).DeleteMany(ctx, bson.M{"project_id": project.ID, "user_id": newOwnerID}); err != nil {
+			return err
+		}
+		_, err = db.Collection(GetUserService().Collection).UpdateOne(ctx,
+			bson.M{"_id": newOwnerID},
+			bson.M{"$pull": bson.M{"team_projects": project.ID}, "$addToSet": bson.M{"owned_projects": project.ID}},
+		)
+		return err
+	})
+	if err != nil {
+		if _, uerr := db.Collection(GetUserService().Collection).UpdateOne(ctx, bson.M{"_id": newOwnerID}, bson.M{"$inc": bson.M{"project_size": -1}}); uerr != nil {
+			log.WithError(uerr).Error("failed to release project slot")
+		}
+		log.WithError(err).Error("failed to transfer project ownership")
+		return err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: project.ID.Hex(),
+		UserID:    project.OwnerID.Hex(),
+		Message:   "Ownership has been transferred -> " + memberName(newOwnerID),
+		Timestamp: time.Now(),
+	}
+	return GetLogService().CreateLog(&projectLog)
+}
+
+// checkPassword loads the user and verifies their password.
+func (s *UserService) checkPassword(ctx context.Context, userID primitive.ObjectID, password string) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, fmt.Errorf("current password is incorrect")
+	}
+	return &user, nil
+}
+
+// parseTransfers maps owned project IDs to their new owners. Only live projects
+// of the user can be transferred, and only to a member of their team.
+func parseTransfers(owned []*models.Project, userID primitive.ObjectID, reqs []request.ProjectTransferRequest) (map[primitive.ObjectID]primitive.ObjectID, error) {
+	transfers := make(map[primitive.ObjectID]primitive.ObjectID, len(reqs))
+	for _, r := range reqs {
+		projectID, err := primitive.ObjectIDFromHex(r.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid project_id %s", r.ProjectID)
+		}
+		newOwnerID, err := primitive.ObjectIDFromHex(r.NewOwnerID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid new_owner_id %s", r.NewOwnerID)
+		}
+
+		i := slices.IndexFunc(owned, func(p *models.Project) bool { return p.ID == projectID })
+		if i < 0 || owned[i].DeletedAt != nil {
+			return nil, fmt.Errorf("project %s is not one of your projects", r.ProjectID)
+		}
+		project := owned[i]
+		if newOwnerID == userID || !slices.Contains(project.TeamIDs, newOwnerID) {
+			return nil, fmt.Errorf("the new owner of %s must be a member of its team", project.Name)
+		}
+		if _, dup := transfers[projectID]; dup {
+			return nil, fmt.Errorf("project %s is transferred twice", project.Name)
+		}
+		transfers[projectID] = newOwnerID
+	}
+	return transfers, nil
+}
+
+// sendEmailChangeEmail queues the confirmation link for the new address.
+func sendEmailChangeEmail(ctx context.Context, name, email, token string) error {
+	return GetMailService().Enqueue(ctx, email, mailer.TemplateEmailChange, map[string]any{
+		"Name":  name,
+		"Link":  frontendURL() + "/verify?change=1&token=" + token,
+		"Hours": int(emailChangeTTL.Hours()),
+	})
+}
@@ -0,0 +1,205 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultAPIKeyDays = 90
+	maxAPIKeyDays     = 365
+	maxAPIKeysPerUser = 20
+	// lastUsedInterval limits how often last_used_at is written.
+	lastUsedInterval = time.Minute
+)
+
+type APIKeyService struct {
+	Collection string
+}
+
+var apiKeyService *APIKeyService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetAPIKeyService() *APIKeyService {
+	if apiKeyService == nil {
+		apiKeyService = &APIKeyService{Collection: "api_keys"}
+	}
+	return apiKeyService
+}
+
+// CreateKey mints a key for the user and returns it together with the plain
+// key, which is not stored and cannot be shown again.
+func (s *APIKeyService) CreateKey(user *models.User, req *request.APIKeyCreateRequest) (*models.APIKey, string, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" || len(name) > 100 {
+		return nil, "", fmt.Errorf("name is required and may have at most 100 characters")
+	}
+
+	scopes := make([]models.APIKeyScope, 0, len(req.Scopes))
+	for _, sc := range req.Scopes {
+		scope := models.APIKeyScope(strings.ToLower(strings.TrimSpace(sc)))
+		if !scope.IsValid() {
+			return nil, "", fmt.Errorf("invalid scope %s", sc)
+		}
+		if scope == models.ScopeAdmin && !user.IsAdmin {
+			return nil, "", fmt.Errorf("only admins can create keys with the admin scope")
+		}
+		if !slices.Contains(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+	if len(scopes) == 0 {
+		scopes = []models.APIKeyScope{models.ScopeRead}
+	}
+
+	days := req.ExpiresInDays
+	if days == 0 {
+		days = defaultAPIKeyDays
+	}
+	if days < 0 || days > maxAPIKeyDays {
+		return nil, "", fmt.Errorf("expires_in_days must be between 1 and %d", maxAPIKeyDays)
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	active, err := collection.CountDocuments(ctx, bson.M{
+		"user_id":    user.ID,
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	if active >= maxAPIKeysPerUser {
+		return nil, "", fmt.Errorf("you can have at most %d active API keys", maxAPIKeysPerUser)
+	}
+
+	secret, err := generateToken(32)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := models.APIKeyPrefix + secret
+
+	key := models.APIKey{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    plain[:len(models.APIKeyPrefix)+8],
+		KeyHash:   hashToken(plain),
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: now.AddDate(0, 0, days),
+	}
+	if _, err := collection.InsertOne(ctx, key); err != nil {
+		log.WithError(err).Error("failed to insert api key")
+		return nil, "", err
+	}
+
+	log.Infof("API key %s created for user %s", key.Prefix, user.ID.Hex())
+	return &key, plain, nil
+}
+
+// GetKeys lists the keys of the user, newest first, including expired and
+// revoked ones.
+func (s *APIKeyService) GetKeys(userID primitive.ObjectID) ([]*models.APIKey, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}})
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	keys := []*models.APIKey{}
+	if err := cursor.All(ctx, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (s *APIKeyService) RevokeKey(userID, keyID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := database.DB.Collection(s.Collection).UpdateOne(ctx,
+		bson.M{"_id": keyID, "user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to revoke api key")
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("api key not found")
+	}
+	return nil
+}
+
+// Authenticate is used by middleware.AuthMiddleware. It returns a nil user for
+// unknown, expired or revoked keys.
+func (s *APIKeyService) Authenticate(plain string) (*models.User, []models.APIKeyScope, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var key models.APIKey
+	err := collection.FindOne(ctx, bson.M{
+		"key_hash":   hashToken(plain),
+		"revoked_at": bson.M{"$exists": false},
+		"expires_at": bson.M{"$gt": now},
+	}).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"full_name": 1, "email": 1, "is_admin": 1, "isverified": 1})
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": key.UserID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil, nil
+		}
+		return nil, nil, err
+	}
+	if !slices.Contains(key.Scopes, models.ScopeAdmin) {
+		user.IsAdmin = false
+	}
+
+	_, err = collection.UpdateOne(ctx,
+		bson.M{"_id": key.ID, "$or": []bson.M{
+			{"last_used_at": bson.M{"$exists": false}},
+			{"last_used_at": bson.M{"$lt": now.Add(-lastUsedInterval)}},
+		}},
+		bson.M{"$set": bson.M{"last_used_at": now}},
+	)
+	if err != nil {
+		log.WithError(err).Warn("failed to update api key last use")
+	}
+
+	return &user, key.Scopes, nil
+}
@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"slices"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+type MemberWorkload struct {
+	UserID     primitive.ObjectID          `json:"user_id"`
+	FullName   string                      `json:"full_name"`
+	Email      string                      `json:"email"`
+	Total      int                         `json:"total"`
+	ByPriority map[models.PriorityType]int `json:"by_priority"`
+	ByStatus   map[string]int              `json:"by_status"`
+}
+
+// AssignIssue assigns an unassigned issue to a member of its project.
+func (s *IssueService) AssignIssue(issueID, assigneeID, userID primitive.ObjectID) (*models.Issue, error) {
+	issue, err := s.GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if !issue.AssigneeID.IsZero() {
+		return nil, fmt.Errorf("issue is already assigned, reassign it instead")
+	}
+	return s.setAssignee(issue, assigneeID, userID)
+}
+
+// ReassignIssue moves an issue to another member, unlinking it from the
+// previous assignee.
+func (s *IssueService) ReassignIssue(issueID, assigneeID, userID primitive.ObjectID) (*models.Issue, error) {
+	issue, err := s.GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.AssigneeID == assigneeID {
+		return nil, fmt.Errorf("issue is already assigned to this user")
+	}
+	return s.setAssignee(issue, assigneeID, userID)
+}
+
+func (s *IssueService) UnassignIssue(issueID, userID primitive.ObjectID) (*models.Issue, error) {
+	issue, err := s.GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if issue.AssigneeID.IsZero() {
+		return nil, fmt.Errorf("issue is not assigned")
+	}
+	return s.setAssignee(issue, primitive.NilObjectID, userID)
+}
+
+// setAssignee keeps Issue.AssigneeID and User.AssignedIssues in sync. A nil
+// assigneeID clears the assignment.
+func (s *IssueService) setAssignee(issue *models.Issue, assigneeID, userID primitive.ObjectID) (*models.Issue, error) {
+	ps := GetProjectService()
+
+	isUserInProject, err := ps.IsUserInProject(userID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+	if err := ps.EnsureWritable(issue.ProjectID); err != nil {
+		return nil, err
+	}
+
+	var assignee *models.User
+	if !assigneeID.IsZero() {
+		isAssigneeInProject, err := ps.IsUserInProject(assigneeID, issue.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAssigneeInProject {
+			return nil, fmt.Errorf("assignee is not part of the project team")
+		}
+
+		assignee, err = GetUserService().GetUserById(assigneeID.Hex())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	issuesColl := database.DB.Collection(s.Collection)
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$unset": bson.M{"assignee_id": ""}}
+	if assignee != nil {
+		update = bson.M{"$set": bson.M{"assignee_id": assigneeID}, "$addToSet": bson.M{"watchers": assigneeID}}
+	}
+	if _, err := issuesColl.UpdateOne(ctx, bson.M{"_id": issue.ID}, update); err != nil {
+		log.WithError(err).Error("failed to update issue assignee")
+		return nil, err
+	}
+
+	previousID := issue.AssigneeID
+	if !previousID.IsZero() {
+		if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": previousID}, bson.M{"$pull": bson.M{"assigned_issues": issue.ID}}); err != nil {
+			log.WithError(err).Error("failed to unlink issue from previous assignee")
+			return nil, err
+		}
+	}
+	if assignee != nil {
+		if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": assigneeID}, bson.M{"$addToSet": bson.M{"assigned_issues": issue.ID}}); err != nil {
+			log.WithError(err).Error("failed to link issue to assignee")
+			return nil, err
+		}
+	}
+
+	var message string
+	switch {
+	case assignee == nil:
+		message = fmt.Sprintf("Issue '%s' has been unassigned", issue.Title)
+	case previousID.IsZero():
+		message = fmt.Sprintf("Issue '%s' has been assigned to %s", issue.Title, assignee.FullName)
+	default:
+		message = fmt.Sprintf("Issue '%s' has been reassigned to %s", issue.Title, assignee.FullName)
+	}
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: issue.ProjectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	issue.AssigneeID = assigneeID
+	if assignee != nil {
+		if !slices.Contains(issue.WatcherIDs, assigneeID) {
+			issue.WatcherIDs = append(issue.WatcherIDs, assigneeID)
+		}
+		GetNotificationService().notifyAssigned(ctx, issue, userID)
+	}
+	return issue, nil
+}
+
+func (s *IssueService) GetAssignedIssues(userID primitive.ObjectID) ([]*models.Issue, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Only issues of live projects the user still belongs to count.
+	projectIDs, err := GetProjectService().GetMemberProjectIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(projectIDs) == 0 {
+		return []*models.Issue{}, nil
+	}
+
+	filter := bson.M{"assignee_id": userID, "project_id": bson.M{"$in": projectIDs}}
+	opts := options.Find().SetSort(bson.D{{Key: "due_date", Value: 1}})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	issues := []*models.Issue{}
+	if err := cursor.All(ctx, &issues); err != nil {
+		return nil, fmt.Errorf("decode issues failed: %w", err)
+	}
+
+	return issues, nil
+}
+
+// GetWorkload counts the assigned issues of every project member by priority
+// and by status column.
+func (s *IssueService) GetWorkload(projectID, userID primitive.ObjectID) ([]*MemberWorkload, error) {
+	ps := GetProjectService()
+
+	isUserInProject, err := ps.IsUserInProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var project models.Project
+	if err := database.DB.Collection(ps.Collection).FindOne(ctx, bson.M{"_id": projectID}).Decode(&project); err != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+
+	memberIDs := append([]primitive.ObjectID{project.OwnerID}, project.TeamIDs...)
+
+	var members []models.User
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	userOpts := options.Find().SetProjection(bson.M{"full_name": 1, "email": 1})
+	userCursor, err := usersColl.Find(ctx, bson.M{"_id": bson.M{"$in": memberIDs}}, userOpts)
+	if err != nil {
+		return nil, err
+	}
+	if err := userCursor.All(ctx, &members); err != nil {
+		return nil, err
+	}
+
+	statuses, err := GetStatusService().GetStatusesByProjectId(projectID)
+	if err != nil {
+		return nil, err
+	}
+	statusNames := make(map[primitive.ObjectID]string, len(statuses))
+	for _, status := range statuses {
+		statusNames[status.ID] = status.Name
+	}
+
+	workloads := make(map[primitive.ObjectID]*MemberWorkload, len(members))
+	result := make([]*MemberWorkload, 0, len(members))
+	for _, member := range members {
+		w := &MemberWorkload{
+			UserID:     member.ID,
+			FullName:   member.FullName,
+			Email:      member.Email,
+			ByPriority: map[models.PriorityType]int{},
+			ByStatus:   map[string]int{},
+		}
+		workloads[member.ID] = w
+		result = append(result, w)
+	}
+
+	filter := bson.M{
+		"project_id":  projectID,
+		"assignee_id": bson.M{"$in": memberIDs},
+	}
+	opts := options.Find().SetProjection(bson.M{"assignee_id": 1, "priority": 1, "status_id": 1})
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var issue models.Issue
+		if err := cursor.Decode(&issue); err != nil {
+			return nil, err
+		}
+		w, ok := workloads[issue.AssigneeID]
+		if !ok {
+			continue
+		}
+
+		priority := issue.Priority
+		if priority == "" {
+			priority = models.Default
+		}
+		statusName, ok := statusNames[issue.StatusID]
+		if !ok {
+			statusName = "UNKNOWN"
+		}
+
+		w.Total++
+		w.ByPriority[priority]++
+		w.ByStatus[statusName]++
+	}
+
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// WatchIssue subscribes a project member to status change notifications of
+// an issue.
+func (s *IssueService) WatchIssue(issueID, userID primitive.ObjectID) (*models.Issue, error) {
+	return s.setWatching(issueID, userID, true)
+}
+
+// UnwatchIssue stops the status change notifications of an issue for a user.
+func (s *IssueService) UnwatchIssue(issueID, userID primitive.ObjectID) (*models.Issue, error) {
+	return s.setWatching(issueID, userID, false)
+}
+
+func (s *IssueService) setWatching(issueID, userID primitive.ObjectID, watch bool) (*models.Issue, error) {
+	issue, err := s.GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$pull": bson.M{"watchers": userID}}
+	if watch {
+		update = bson.M{"$addToSet": bson.M{"watchers": userID}}
+	}
+	if _, err := database.DB.Collection(s.Collection).UpdateOne(ctx, bson.M{"_id": issue.ID}, update); err != nil {
+		log.WithError(err).Error("failed to update issue watchers")
+		return nil, err
+	}
+
+	issue.WatcherIDs = slices.DeleteFunc(issue.WatcherIDs, func(id primitive.ObjectID) bool { return id == userID })
+	if watch {
+		issue.WatcherIDs = append(issue.WatcherIDs, userID)
+	}
+	return issue, nil
+}
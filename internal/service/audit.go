@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"managify/database"
+	"managify/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type AuditService struct {
+	Collection string
+}
+
+var auditService *AuditService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetAuditService() *AuditService {
+	if auditService == nil {
+		auditService = &AuditService{Collection: "audit_logs"}
+	}
+	return auditService
+}
+
+// Record stores an audit entry. Failures are logged and not returned so that
+// auditing never blocks the audited operation.
+func (s *AuditService) Record(entry *models.AuditLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	entry.ID = primitive.NewObjectID()
+	if entry.Timestamp.IsZero() {
+		entry.Timestamp = time.Now()
+	}
+	if _, err := database.DB.Collection(s.Collection).InsertOne(ctx, entry); err != nil {
+		log.WithError(err).Errorf("failed to record audit entry %s", entry.Action)
+	}
+}
+
+// GetAuditLogs returns the newest entries first, optionally of one action.
+func (s *AuditService) GetAuditLogs(action string, limit int64) ([]*models.AuditLog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if action != "" {
+		filter["action"] = action
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}}).SetLimit(limit)
+
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	entries := []*models.AuditLog{}
+	if err := cursor.All(ctx, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
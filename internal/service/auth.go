@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultRefreshTokenDays = 30
+
+// AuthService manages refresh tokens and the access token revocation list.
+type AuthService struct {
+	Collection        string
+	RevokedCollection string
+}
+
+var authService *AuthService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetAuthService() *AuthService {
+	if authService == nil {
+		authService = &AuthService{Collection: "refresh_tokens", RevokedCollection: "revoked_tokens"}
+	}
+	return authService
+}
+
+// SessionInfo describes the client a refresh token is issued to.
+type SessionInfo struct {
+	UserAgent string
+	IP        string
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"token"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// refreshTokenTTL reads REFRESH_TOKEN_TTL_DAYS, defaulting to 30 days.
+func refreshTokenTTL() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_TTL_DAYS"))
+	if err != nil || days <= 0 {
+		days = defaultRefreshTokenDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// IssueRefreshToken starts a new token family for a fresh login.
+func (s *AuthService) IssueRefreshToken(userID primitive.ObjectID, info SessionInfo) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	token, _, err := s.insertRefreshToken(ctx, userID, primitive.NewObjectID(), info)
+	return token, err
+}
+
+// Refresh rotates a refresh token: the presented token is marked as used and a
+// new access and refresh token are returned. Presenting a token that was
+// already used or revoked is treated as theft and revokes its whole family.
+func (s *AuthService) Refresh(refreshToken string, info SessionInfo) (*TokenPair, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var current models.RefreshToken
+	if err := collection.FindOne(ctx, bson.M{"token_hash": hashToken(refreshToken)}).Decode(&current); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("invalid refresh token")
+		}
+		return nil, err
+	}
+
+	if current.UsedAt != nil || current.RevokedAt != nil {
+		log.Warnf("refresh token reuse detected for user %s, revoking family %s", current.UserID.Hex(), current.FamilyID.Hex())
+		if err := s.revokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("refresh token has already been used")
+	}
+	if time.Now().After(current.ExpiresAt) {
+		return nil, fmt.Errorf("refresh token has expired")
+	}
+
+	user, err := GetUserService().GetUserById(current.UserID.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	newToken, newID, err := s.insertRefreshToken(ctx, current.UserID, current.FamilyID, info)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the first concurrent refresh wins; a loser means the token was
+	// replayed and the family is revoked.
+	now := time.Now()
+	res, err := collection.UpdateOne(ctx,
+		bson.M{"_id": current.ID, "used_at": bson.M{"$exists": false}, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"used_at": now, "replaced_by": newID}},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if res.ModifiedCount == 0 {
+		if err := s.revokeFamily(ctx, current.FamilyID); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("refresh token has already been used")
+	}
+
+	accessToken, err := GetUserService().CreateToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token")
+	}
+
+	return &TokenPair{AccessToken: accessToken, RefreshToken: newToken}, nil
+}
+
+// Logout revokes the access token identified by jti and, when given, the
+// session of the refresh token.
+func (s *AuthService) Logout(userID primitive.ObjectID, jti string, accessExpiresAt time.Time, refreshToken string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if jti != "" {
+		if err := s.revokeAccessToken(ctx, userID, jti, accessExpiresAt); err != nil {
+			return err
+		}
+	}
+
+	if refreshToken == "" {
+		return nil
+	}
+
+	var current models.RefreshToken
+	err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"token_hash": hashToken(refreshToken), "user_id": userID}).Decode(&current)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+	return s.revokeFamily(ctx, current.FamilyID)
+}
+
+// LogoutAll revokes every refresh token of the user and every access token
+// issued up to now.
+func (s *AuthService) LogoutAll(userID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"tokens_valid_after": now}}); err != nil {
+		log.WithError(err).Error("failed to invalidate access tokens")
+		return err
+	}
+
+	_, err := database.DB.Collection(s.Collection).UpdateMany(ctx,
+		bson.M{"user_id": userID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": now}},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to revoke refresh tokens")
+		return err
+	}
+
+	log.Infof("All sessions revoked for user %s", userID.Hex())
+	return nil
+}
+
+// IsAccessTokenRevoked is used by middleware.AuthMiddleware.
+func (s *AuthService) IsAccessTokenRevoked(jti string, userID primitive.ObjectID, issuedAt time.Time) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if jti != "" {
+		count, err := database.DB.Collection(s.RevokedCollection).CountDocuments(ctx, bson.M{"_id": jti})
+		if err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return true, nil
+		}
+	}
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"tokens_valid_after": 1})
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return true, nil
+		}
+		return false, err
+	}
+
+	// JWT iat has second precision.
+	return user.TokensValidAfter != nil && issuedAt.Before(user.TokensValidAfter.Truncate(time.Second)), nil
+}
+
+func (s *AuthService) insertRefreshToken(ctx context.Context, userID, familyID primitive.ObjectID, info SessionInfo) (string, primitive.ObjectID, error) {
+	token, err := generateToken(32)
+	if err != nil {
+		return "", primitive.NilObjectID, err
+	}
+
+	now := time.Now()
+	record := models.RefreshToken{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		FamilyID:  familyID,
+		TokenHash: hashToken(token),
+		UserAgent: info.UserAgent,
+		IP:        info.IP,
+		CreatedAt: now,
+		ExpiresAt: now.Add(refreshTokenTTL()),
+	}
+	if _, err := database.DB.Collection(s.Collection).InsertOne(ctx, record); err != nil {
+		log.WithError(err).Error("failed to insert refresh token")
+		return "", primitive.NilObjectID, err
+	}
+	return token, record.ID, nil
+}
+
+func (s *AuthService) revokeFamily(ctx context.Context, familyID primitive.ObjectID) error {
+	_, err := database.DB.Collection(s.Collection).UpdateMany(ctx,
+		bson.M{"family_id": familyID, "revoked_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"revoked_at": time.Now()}},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to revoke refresh token family")
+	}
+	return err
+}
+
+func (s *AuthService) revokeAccessToken(ctx context.Context, userID primitive.ObjectID, jti string, expiresAt time.Time) error {
+	if expiresAt.IsZero() {
+		expiresAt = time.Now().Add(time.Hour)
+	}
+	revoked := models.RevokedToken{JTI: jti, UserID: userID, ExpiresAt: expiresAt}
+	opts := options.Replace().SetUpsert(true)
+	if _, err := database.DB.Collection(s.RevokedCollection).ReplaceOne(ctx, bson.M{"_id": jti}, revoked, opts); err != nil {
+		log.WithError(err).Error("failed to revoke access token")
+		return err
+	}
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,349 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/internal/realtime"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BoardService serves the Kanban view of a project. The column order is kept
+// in Status.Position and the card order in Status.IssueIDs.
+type BoardService struct{}
+
+var boardService *BoardService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetBoardService() *BoardService {
+	if boardService == nil {
+		boardService = &BoardService{}
+	}
+	return boardService
+}
+
+type BoardColumn struct {
+	ID       primitive.ObjectID `json:"id"`
+	Name     string             `json:"name"`
+	Position int                `json:"position"`
+	Issues   []*models.Issue    `json:"issues"`
+}
+
+type Board struct {
+	ProjectID primitive.ObjectID `json:"project_id"`
+	Columns   []*BoardColumn     `json:"columns"`
+}
+
+// GetBoard returns the statuses of a project in column order, each with its
+// issues in card order.
+func (s *BoardService) GetBoard(projectID primitive.ObjectID) (*Board, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses, err := s.projectStatuses(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+	cursor, err := issuesColl.Find(ctx, bson.M{"project_id": projectID, "status_id": bson.M{"$exists": true}})
+	if err != nil {
+		return nil, err
+	}
+	var issues []*models.Issue
+	if err := cursor.All(ctx, &issues); err != nil {
+		return nil, err
+	}
+
+	byStatus := map[primitive.ObjectID][]*models.Issue{}
+	for _, issue := range issues {
+		byStatus[issue.StatusID] = append(byStatus[issue.StatusID], issue)
+	}
+
+	board := &Board{ProjectID: projectID, Columns: make([]*BoardColumn, 0, len(statuses))}
+	for i, status := range statuses {
+		column := &BoardColumn{
+			ID:       status.ID,
+			Name:     status.Name,
+			Position: i,
+			Issues:   orderIssues(status.IssueIDs, byStatus[status.ID]),
+		}
+		board.Columns = append(board.Columns, column)
+	}
+
+	return board, nil
+}
+
+// IssueMove describes where an issue goes on the board. A nil Position means
+// the bottom of the column.
+type IssueMove struct {
+	StatusID   primitive.ObjectID
+	Position   *int
+	Resolution string
+}
+
+// MoveIssue puts an issue into a column at the given position. Moving within
+// the same column only reorders it; moving to another column must be allowed
+// by the project workflow. Status.IssueIDs of both columns and Issue.StatusID
+// are updated in one transaction.
+func (s *BoardService) MoveIssue(issueID, userID primitive.ObjectID, move IssueMove) (*models.Issue, error) {
+	toStatusID, position := move.StatusID, move.Position
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	issue, err := GetIssueService().GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+	if err := GetProjectService().EnsureWritable(issue.ProjectID); err != nil {
+		return nil, err
+	}
+	if position != nil && *position < 0 {
+		return nil, fmt.Errorf("position must not be negative")
+	}
+
+	statusColl := database.DB.Collection(GetStatusService().Collection)
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+
+	var target models.Status
+	if err := statusColl.FindOne(ctx, bson.M{"_id": toStatusID, "project_id": issue.ProjectID}).Decode(&target); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("status not found in project")
+		}
+		return nil, err
+	}
+	if err := GetWorkflowService().CheckTransition(ctx, issue, &target, userID, move.Resolution); err != nil {
+		return nil, err
+	}
+
+	fromStatusID := issue.StatusID
+	resolution := strings.TrimSpace(move.Resolution)
+	now := time.Now()
+	var at int
+
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		if err := statusColl.FindOne(ctx, bson.M{"_id": toStatusID}).Decode(&target); err != nil {
+			return err
+		}
+		columnIssues, err := s.columnIssues(ctx, toStatusID)
+		if err != nil {
+			return err
+		}
+
+		order := make([]primitive.ObjectID, 0, len(columnIssues)+1)
+		for _, ci := range orderIssues(target.IssueIDs, columnIssues) {
+			if ci.ID != issueID {
+				order = append(order, ci.ID)
+			}
+		}
+		at = len(order)
+		if position != nil && *position < at {
+			at = *position
+		}
+		order = slices.Insert(order, at, issueID)
+
+		if _, err := statusColl.UpdateOne(ctx, bson.M{"_id": toStatusID}, bson.M{"$set": bson.M{"issues": order, "updated_at": now}}); err != nil {
+			return err
+		}
+		if !fromStatusID.IsZero() && fromStatusID != toStatusID {
+			update := bson.M{"$pull": bson.M{"issues": issueID}, "$set": bson.M{"updated_at": now}}
+			if _, err := statusColl.UpdateOne(ctx, bson.M{"_id": fromStatusID}, update); err != nil {
+				return err
+			}
+		}
+		if fromStatusID != toStatusID {
+			set := bson.M{"status_id": toStatusID, "updated_at": now}
+			if resolution != "" {
+				set["resolution"] = resolution
+			}
+			if _, err := issuesColl.UpdateOne(ctx, bson.M{"_id": issueID}, bson.M{"$set": set}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to move issue")
+		return nil, fmt.Errorf("failed to move issue: %w", err)
+	}
+
+	if fromStatusID == toStatusID {
+		publishMove(issue, userID, fromStatusID, at)
+		return issue, nil
+	}
+
+	changes := []models.FieldChange{{
+		Field:  "status",
+		Before: GetIssueService().statusName(fromStatusID),
+		After:  target.Name,
+	}}
+	if resolution != "" && resolution != issue.Resolution {
+		changes = append(changes, models.FieldChange{Field: "resolution", Before: issue.Resolution, After: resolution})
+		issue.Resolution = resolution
+	}
+	history := models.IssueHistory{
+		IssueID:   issue.ID,
+		ProjectID: issue.ProjectID,
+		UserID:    userID,
+		Changes:   changes,
+	}
+	if err := GetIssueHistoryService().CreateHistory(&history); err != nil {
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: issue.ProjectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   fmt.Sprintf("Issue '%s' %s", issue.Title, describeChanges(history.Changes)),
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	issue.StatusID = toStatusID
+	issue.UpdatedAt = now
+	publishMove(issue, userID, fromStatusID, at)
+	GetNotificationService().notifyStatusChanged(ctx, issue, userID, fmt.Sprint(changes[0].Before), target.Name)
+	return issue, nil
+}
+
+// publishMove tells the board's viewers that issue now sits at position in
+// its column.
+func publishMove(issue *models.Issue, userID, fromStatusID primitive.ObjectID, position int) {
+	publishEvent(issue.ProjectID, userID, realtime.IssueMoved, map[string]any{
+		"issue":          issue,
+		"from_status_id": fromStatusID,
+		"to_status_id":   issue.StatusID,
+		"position":       position,
+	})
+}
+
+// ReorderColumns sets the column order of a project. statusIDs must list every
+// status of the project exactly once.
+func (s *BoardService) ReorderColumns(projectID primitive.ObjectID, statusIDs []primitive.ObjectID, userID primitive.ObjectID) (*Board, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	statuses, err := s.projectStatuses(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if len(statusIDs) != len(statuses) {
+		return nil, fmt.Errorf("expected %d statuses, got %d", len(statuses), len(statusIDs))
+	}
+	for _, status := range statuses {
+		if !slices.Contains(statusIDs, status.ID) {
+			return nil, fmt.Errorf("status %s is missing from the new order", status.Name)
+		}
+	}
+
+	statusColl := database.DB.Collection(GetStatusService().Collection)
+	now := time.Now()
+	writes := make([]mongo.WriteModel, 0, len(statusIDs))
+	for i, id := range statusIDs {
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": id, "project_id": projectID}).
+			SetUpdate(bson.M{"$set": bson.M{"position": i, "updated_at": now}}))
+	}
+
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		_, err := statusColl.BulkWrite(ctx, writes)
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to reorder statuses")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Board Columns Have Been Reordered",
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return s.GetBoard(projectID)
+}
+
+func (s *BoardService) projectStatuses(ctx context.Context, projectID primitive.ObjectID) ([]*models.Status, error) {
+	statusColl := database.DB.Collection(GetStatusService().Collection)
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := statusColl.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var statuses []*models.Status
+	if err := cursor.All(ctx, &statuses); err != nil {
+		return nil, err
+	}
+	return statuses, nil
+}
+
+func (s *BoardService) columnIssues(ctx context.Context, statusID primitive.ObjectID) ([]*models.Issue, error) {
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+	cursor, err := issuesColl.Find(ctx, bson.M{"status_id": statusID})
+	if err != nil {
+		return nil, err
+	}
+	var issues []*models.Issue
+	if err := cursor.All(ctx, &issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+// orderIssues sorts issues by their index in order. Issues missing from order,
+// such as ones created before positions were tracked, go last in creation order.
+func orderIssues(order []primitive.ObjectID, issues []*models.Issue) []*models.Issue {
+	rank := make(map[primitive.ObjectID]int, len(order))
+	for i, id := range order {
+		rank[id] = i
+	}
+
+	sorted := slices.Clone(issues)
+	slices.SortStableFunc(sorted, func(a, b *models.Issue) int {
+		ra, aok := rank[a.ID]
+		rb, bok := rank[b.ID]
+		switch {
+		case aok && bok:
+			return ra - rb
+		case aok:
+			return -1
+		case bok:
+			return 1
+		}
+		return bytes.Compare(a.ID[:], b.ID[:])
+	})
+	if sorted == nil {
+		sorted = []*models.Issue{}
+	}
+	return sorted
+}
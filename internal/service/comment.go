@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type CommentService struct {
+	Collection string
+}
+
+var commentService *CommentService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetCommentService() *CommentService {
+	if commentService == nil {
+		commentService = &CommentService{Collection: "comments"}
+	}
+	return commentService
+}
+
+// CreateComment posts a comment on an issue. When parentID is set the comment
+// is a reply inside the thread started by that parent.
+func (s *CommentService) CreateComment(issueID, parentID, userID primitive.ObjectID, body string) (*models.Comment, error) {
+	issue, err := GetIssueService().GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+	if err := GetProjectService().EnsureWritable(issue.ProjectID); err != nil {
+		return nil, err
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if !parentID.IsZero() {
+		var parent models.Comment
+		err := collection.FindOne(ctx, bson.M{"_id": parentID, "issue_id": issueID}).Decode(&parent)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("parent comment not found")
+			}
+			return nil, err
+		}
+		if parent.IsDeleted {
+			return nil, fmt.Errorf("cannot reply to a deleted comment")
+		}
+		// Replies are kept one level deep so threads stay readable.
+		if !parent.ParentID.IsZero() {
+			parentID = parent.ParentID
+		}
+	}
+
+	comment := &models.Comment{
+		ID:        primitive.NewObjectID(),
+		IssueID:   issueID,
+		ProjectID: issue.ProjectID,
+		AuthorID:  userID,
+		ParentID:  parentID,
+		Body:      body,
+		CreatedAt: time.Now(),
+	}
+
+	if _, err := collection.InsertOne(ctx, comment); err != nil {
+		log.WithError(err).Error("failed to insert comment")
+		return nil, err
+	}
+
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+	if _, err := issuesColl.UpdateOne(ctx, bson.M{"_id": issueID}, bson.M{"$push": bson.M{"comments": comment.ID}}); err != nil {
+		log.WithError(err).Error("failed to link comment to issue")
+		return nil, err
+	}
+
+	message := fmt.Sprintf("Comment has been added to '%s'", issue.Title)
+	if !parentID.IsZero() {
+		message = fmt.Sprintf("Reply has been added to '%s'", issue.Title)
+	}
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: issue.ProjectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return comment, nil
+}
+
+func (s *CommentService) GetCommentsByIssueID(issueID, userID primitive.ObjectID) ([]*models.Comment, error) {
+	issue, err := GetIssueService().GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"issue_id": issueID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	comments := []*models.Comment{}
+	if err := cursor.All(ctx, &comments); err != nil {
+		return nil, fmt.Errorf("decode comments failed: %w", err)
+	}
+
+	return comments, nil
+}
+
+func (s *CommentService) UpdateComment(commentID, userID primitive.ObjectID, body string) (*models.Comment, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	comment, err := s.getComment(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted {
+		return nil, fmt.Errorf("comment has been deleted")
+	}
+	if comment.AuthorID != userID {
+		return nil, fmt.Errorf("only the author can edit this comment")
+	}
+
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, comment.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+	if err := GetProjectService().EnsureWritable(comment.ProjectID); err != nil {
+		return nil, err
+	}
+
+	editedAt := time.Now()
+	update := bson.M{"$set": bson.M{"body": body, "edited_at": editedAt}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": commentID}, update); err != nil {
+		log.WithError(err).Error("failed to update comment")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: comment.ProjectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Comment has been edited",
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	comment.Body = body
+	comment.EditedAt = &editedAt
+	return comment, nil
+}
+
+// DeleteComment soft-deletes a comment so replies keep their place in the
+// thread. The author and members with comment.moderate are allowed to delete.
+func (s *CommentService) DeleteComment(commentID, userID primitive.ObjectID) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	comment, err := s.getComment(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.IsDeleted {
+		return fmt.Errorf("comment has already been deleted")
+	}
+	if err := GetProjectService().EnsureWritable(comment.ProjectID); err != nil {
+		return err
+	}
+
+	if comment.AuthorID != userID {
+		if err := GetRoleService().Authorize(userID, comment.ProjectID, models.PermCommentModerate); err != nil {
+			return fmt.Errorf("user is not allowed to delete this comment")
+		}
+	}
+
+	update := bson.M{"$set": bson.M{
+		"is_deleted": true,
+		"deleted_at": time.Now(),
+		"body":       "",
+	}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": commentID}, update); err != nil {
+		log.WithError(err).Error("failed to delete comment")
+		return err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: comment.ProjectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Comment has been deleted",
+		Timestamp: time.Now(),
+	}
+	return GetLogService().CreateLog(&projectLog)
+}
+
+func (s *CommentService) DeleteCommentsByIssueID(issueID primitive.ObjectID) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := collection.DeleteMany(ctx, bson.M{"issue_id": issueID}); err != nil {
+		log.WithError(err).Error("failed to delete issue comments")
+		return err
+	}
+	return nil
+}
+
+func (s *CommentService) GetCommentById(commentID primitive.ObjectID) (*models.Comment, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.getComment(ctx, commentID)
+}
+
+func (s *CommentService) getComment(ctx context.Context, commentID primitive.ObjectID) (*models.Comment, error) {
+	collection := database.DB.Collection(s.Collection)
+
+	var comment models.Comment
+	if err := collection.FindOne(ctx, bson.M{"_id": commentID}).Decode(&comment); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("comment not found")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"managify/database"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// withMockDB runs fn against a mocked MongoDB deployment. Replies are queued
+// with mt.AddMockResponses in the order the code under test sends commands.
+func withMockDB(t *testing.T, fn func(mt *mtest.T)) {
+	t.Helper()
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+	mt.Run("mock", func(mt *mtest.T) {
+		prev := database.DB
+		database.DB = mt.DB
+		defer func() { database.DB = prev }()
+		fn(mt)
+	})
+}
+
+// docsReply answers a find or aggregate command with docs.
+func docsReply(t testing.TB, docs ...any) bson.D {
+	t.Helper()
+	batch := make([]bson.D, len(docs))
+	for i, doc := range docs {
+		batch[i] = docsValue(t, doc)
+	}
+	return mtest.CreateCursorResponse(0, "test.mock", mtest.FirstBatch, batch...)
+}
+
+// docsValue converts a model to the document the server would return.
+func docsValue(t testing.TB, doc any) bson.D {
+	t.Helper()
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var d bson.D
+	if err := bson.Unmarshal(raw, &d); err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// countReply answers CountDocuments.
+func countReply(t testing.TB, n int64) bson.D {
+	if n == 0 {
+		return docsReply(t)
+	}
+	return docsReply(t, bson.M{"n": n})
+}
+
+// sentCommands lists the names of the commands sent so far.
+func sentCommands(mt *mtest.T) []string {
+	var names []string
+	for _, e := range mt.GetAllStartedEvents() {
+		names = append(names, e.CommandName)
+	}
+	return names
+}
+
+// updateReply answers an update command.
+func updateReply(modified int) bson.D {
+	return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: modified}, bson.E{Key: "nModified", Value: modified})
+}
+
+// sentUpdate returns the filter and update of the next update command.
+func sentUpdate(t *testing.T, mt *mtest.T) (bson.Raw, bson.Raw) {
+	t.Helper()
+	e := mt.GetStartedEvent()
+	if e == nil || e.CommandName != "update" {
+		t.Fatalf("expected an update command, got %v", e)
+	}
+	stmt := e.Command.Lookup("updates", "0").Document()
+	return stmt.Lookup("q").Document(), stmt.Lookup("u").Document()
+}
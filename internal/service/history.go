@@ -0,0 +1,119 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type IssueHistoryService struct {
+	Collection string
+}
+
+var issueHistoryService *IssueHistoryService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetIssueHistoryService() *IssueHistoryService {
+	if issueHistoryService == nil {
+		issueHistoryService = &IssueHistoryService{Collection: "issue_history"}
+	}
+	return issueHistoryService
+}
+
+func (s *IssueHistoryService) CreateHistory(history *models.IssueHistory) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	history.ID = primitive.NewObjectID()
+	if history.Timestamp.IsZero() {
+		history.Timestamp = time.Now()
+	}
+
+	if _, err := collection.InsertOne(ctx, history); err != nil {
+		log.WithError(err).Error("failed to insert issue history")
+		return err
+	}
+
+	return nil
+}
+
+func (s *IssueHistoryService) GetHistoryByIssueID(issueID, userID primitive.ObjectID) ([]*models.IssueHistory, error) {
+	issue, err := GetIssueService().GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "timestamp", Value: -1}})
+	cursor, err := collection.Find(ctx, bson.M{"issue_id": issueID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	history := []*models.IssueHistory{}
+	if err := cursor.All(ctx, &history); err != nil {
+		return nil, fmt.Errorf("decode issue history failed: %w", err)
+	}
+
+	return history, nil
+}
+
+func (s *IssueHistoryService) DeleteHistoryByIssueID(issueID primitive.ObjectID) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := collection.DeleteMany(ctx, bson.M{"issue_id": issueID}); err != nil {
+		log.WithError(err).Error("failed to delete issue history")
+		return err
+	}
+	return nil
+}
+
+// describeChanges renders field changes as a short human readable summary,
+// e.g. "priority HIGH → URGENT, title 'Login' → 'Login page'".
+func describeChanges(changes []models.FieldChange) string {
+	parts := make([]string, 0, len(changes))
+	for _, change := range changes {
+		switch change.Field {
+		case "description":
+			parts = append(parts, "description edited")
+		case "title", "due_date":
+			parts = append(parts, fmt.Sprintf("%s '%v' → '%v'", change.Field, change.Before, change.After))
+		case "tags":
+			parts = append(parts, fmt.Sprintf("tags %v → %v", change.Before, change.After))
+		default:
+			parts = append(parts, fmt.Sprintf("%s %v → %v", change.Field, change.Before, change.After))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
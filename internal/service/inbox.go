@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultInboxLimit = 20
+	maxInboxLimit     = 100
+)
+
+// InboxService keeps the in-app notifications shown behind the bell icon.
+// Entries are written by NotificationService for every notification,
+// regardless of the user's email preferences.
+type InboxService struct {
+	Collection string
+}
+
+var inboxService *InboxService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetInboxService() *InboxService {
+	if inboxService == nil {
+		inboxService = &InboxService{Collection: "notifications"}
+	}
+	return inboxService
+}
+
+func (s *InboxService) add(ctx context.Context, n Notification) error {
+	entry := models.Notification{
+		ID:        primitive.NewObjectID(),
+		UserID:    n.UserID,
+		ActorID:   n.ActorID,
+		ProjectID: n.ProjectID,
+		Event:     n.Event,
+		Title:     n.Title,
+		Message:   n.Message,
+		Link:      n.Link,
+		CreatedAt: time.Now(),
+	}
+	_, err := database.DB.Collection(s.Collection).InsertOne(ctx, entry)
+	return err
+}
+
+// GetNotifications lists the notifications of a user, newest first. Pages
+// continue with the ID of the last entry of the previous page as before.
+func (s *InboxService) GetNotifications(userID primitive.ObjectID, unreadOnly bool, before primitive.ObjectID, limit int) ([]*models.Notification, error) {
+	if limit <= 0 || limit > maxInboxLimit {
+		limit = defaultInboxLimit
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"user_id": userID}
+	if unreadOnly {
+		filter["read"] = false
+	}
+	if !before.IsZero() {
+		filter["_id"] = bson.M{"$lt": before}
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}}).SetLimit(int64(limit))
+
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	notifications := []*models.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, err
+	}
+	return notifications, nil
+}
+
+// UnreadCount returns how many unread notifications a user has.
+func (s *InboxService) UnreadCount(userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return database.DB.Collection(s.Collection).CountDocuments(ctx, bson.M{"user_id": userID, "read": false})
+}
+
+// SetRead marks a notification of the user as read or unread.
+func (s *InboxService) SetRead(userID, notificationID primitive.ObjectID, read bool) (*models.Notification, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	update := bson.M{"$set": bson.M{"read": false}, "$unset": bson.M{"read_at": ""}}
+	if read {
+		update = bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}}
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var notification models.Notification
+	err := database.DB.Collection(s.Collection).FindOneAndUpdate(ctx,
+		bson.M{"_id": notificationID, "user_id": userID}, update, opts,
+	).Decode(&notification)
+	if err != nil {
+		return nil, fmt.Errorf("notification not found")
+	}
+	return &notification, nil
+}
+
+// MarkAllRead marks every unread notification of the user as read and
+// returns how many were changed.
+func (s *InboxService) MarkAllRead(userID primitive.ObjectID) (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := database.DB.Collection(s.Collection).UpdateMany(ctx,
+		bson.M{"user_id": userID, "read": false},
+		bson.M{"$set": bson.M{"read": true, "read_at": time.Now()}},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to mark notifications as read")
+		return 0, err
+	}
+	return res.ModifiedCount, nil
+}
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"managify/database"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+// collectionIndexes lists the indexes each collection needs for the query
+// patterns used by the services.
+func collectionIndexes() map[string][]mongo.IndexModel {
+	return map[string][]mongo.IndexModel{
+		GetProjectService().Collection: {
+			{
+				Keys: bson.D{{Key: "name", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}},
+				Options: options.Index().SetName("project_text").
+					SetWeights(bson.D{{Key: "name", Value: 10}, {Key: "tags", Value: 5}, {Key: "description", Value: 1}}),
+			},
+		},
+		GetIssueService().Collection: {
+			{
+				Keys: bson.D{{Key: "title", Value: "text"}, {Key: "description", Value: "text"}, {Key: "tags", Value: "text"}},
+				Options: options.Index().SetName("issue_text").
+					SetWeights(bson.D{{Key: "title", Value: 10}, {Key: "tags", Value: 5}, {Key: "description", Value: 1}}),
+			},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status_id", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "priority", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "status", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "due_date", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "assignee_id", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "tags", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "updated_at", Value: -1}}},
+			{Keys: bson.D{{Key: "assignee_id", Value: 1}, {Key: "due_date", Value: 1}}},
+			{Keys: bson.D{{Key: "status_id", Value: 1}}},
+			{Keys: bson.D{{Key: "due_date", Value: 1}}},
+			{Keys: bson.D{{Key: "watchers", Value: 1}}},
+		},
+		GetCommentService().Collection: {
+			{
+				Keys:    bson.D{{Key: "body", Value: "text"}},
+				Options: options.Index().SetName("comment_text"),
+			},
+			{Keys: bson.D{{Key: "issue_id", Value: 1}, {Key: "created_at", Value: 1}}},
+		},
+		GetStatusService().Collection: {
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "position", Value: 1}}},
+		},
+		GetRoleService().Collection: {
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "definition_id", Value: 1}}},
+		},
+		GetRoleDefinitionService().Collection: {
+			{
+				Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "name", Value: 1}},
+				Options: options.Index().SetUnique(true).
+					SetCollation(&options.Collation{Locale: "en", Strength: 2}),
+			},
+		},
+		GetWorkflowService().Collection: {
+			{Keys: bson.D{{Key: "project_id", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		GetProjectTemplateService().Collection: {
+			{Keys: bson.D{{Key: "key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		},
+		GetAuthService().Collection: {
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "family_id", Value: 1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		GetAuthService().RevokedCollection: {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		loginAttemptsCollection: {
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		GetAuditService().Collection: {
+			{Keys: bson.D{{Key: "timestamp", Value: -1}}},
+			{Keys: bson.D{{Key: "action", Value: 1}, {Key: "timestamp", Value: -1}}},
+		},
+		GetTwoFactorService().Collection: {
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		GetPasswordResetService().Collection: {
+			{Keys: bson.D{{Key: "token_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "user_id", Value: 1}}},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		GetMailService().Collection: {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+			{Keys: bson.D{{Key: "created_at", Value: -1}}},
+			// Delivered and failed emails are kept for a week as a delivery record.
+			{Keys: bson.D{{Key: "sent_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(7 * 24 * 3600)},
+			{Keys: bson.D{{Key: "failed_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(7 * 24 * 3600)},
+		},
+		GetWebhookService().Collection: {
+			{Keys: bson.D{{Key: "project_id", Value: 1}, {Key: "active", Value: 1}}},
+		},
+		GetWebhookService().Deliveries: {
+			{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+			{Keys: bson.D{{Key: "webhook_id", Value: 1}, {Key: "created_at", Value: -1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}}},
+			// Deliveries are kept for 30 days.
+			{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(30 * 24 * 3600)},
+		},
+		GetInboxService().Collection: {
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "_id", Value: -1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "read", Value: 1}}},
+			{Keys: bson.D{{Key: "project_id", Value: 1}}},
+			// The inbox keeps the last 90 days.
+			{Keys: bson.D{{Key: "created_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(90 * 24 * 3600)},
+		},
+		GetNotificationService().Collection: {
+			{Keys: bson.D{{Key: "digest_at", Value: 1}}},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "digest_at", Value: 1}}},
+		},
+		GetUserService().Collection: {
+			{
+				Keys: bson.D{{Key: "oidc_issuer", Value: 1}, {Key: "oidc_subject", Value: 1}},
+				Options: options.Index().SetUnique(true).
+					SetPartialFilterExpression(bson.M{"oidc_subject": bson.M{"$exists": true}}),
+			},
+		},
+		GetOIDCService().Collection: {
+			{Keys: bson.D{{Key: "state_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "expires_at", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(0)},
+		},
+		GetAPIKeyService().Collection: {
+			{Keys: bson.D{{Key: "key_hash", Value: 1}}, Options: options.Index().SetUnique(true)},
+			{Keys: bson.D{{Key: "user_id", Value: 1}, {Key: "created_at", Value: -1}}},
+		},
+		GetIssueHistoryService().Collection: {
+			{Keys: bson.D{{Key: "issue_id", Value: 1}, {Key: "timestamp", Value: -1}}},
+		},
+	}
+}
+
+// EnsureIndexes creates the indexes declared in collectionIndexes. Creating an
+// index that already exists is a no-op in MongoDB, so this is safe on every start.
+func EnsureIndexes() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var firstErr error
+	for name, indexes := range collectionIndexes() {
+		if _, err := database.DB.Collection(name).Indexes().CreateMany(ctx, indexes); err != nil {
+			log.WithError(err).Errorf("failed to create indexes for %s", name)
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+
+	return firstErr
+}
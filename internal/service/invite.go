@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"managify/database"
 	"managify/dto/request"
+	"managify/internal/realtime"
 	"managify/models"
 	"sync"
 	"time"
@@ -60,7 +61,7 @@ func CreateProjectInvite(senderID primitive.ObjectID, req request.ProjectInviteR
 			return
 		}
 
-		projectsCollFilter := bson.M{"_id": projectID}
+		projectsCollFilter := bson.M{"_id": projectID, "deleted_at": bson.M{"$exists": false}}
 		if err := projectsColl.FindOne(ctx, projectsCollFilter).Decode(&project); err != nil {
 			errChan <- fmt.Errorf("project not found")
 			return
@@ -129,6 +130,22 @@ func CreateProjectInvite(senderID primitive.ObjectID, req request.ProjectInviteR
 		return nil, err
 	}
 
+	GetWebhookService().Dispatch(projectID, senderID, models.WebhookInviteSent, map[string]any{
+		"invite_id":   invite.ID,
+		"receiver_id": receiver.ID,
+		"status":      invite.Status,
+	})
+
+	GetNotificationService().Notify(Notification{
+		UserID:    receiver.ID,
+		ActorID:   senderID,
+		ProjectID: projectID,
+		Event:     models.EventInviteReceived,
+		Title:     fmt.Sprintf("You have been invited to %s", project.Name),
+		Message:   fmt.Sprintf("%s invited you to join the project %s.", displayName(ctx, senderID), project.Name),
+		Link:      frontendURL() + "/dashboard",
+	})
+
 	return &invite, nil
 }
 
@@ -245,8 +262,41 @@ func RespondProjectInvite(userID, inviteID primitive.ObjectID, accept bool) (*mo
 			return nil, err
 		}
 		log.Infof("User %s added to project %s team", userID.Hex(), invite.ProjectID.Hex())
+		publishEvent(invite.ProjectID, userID, realtime.MemberJoined, map[string]any{
+			"user_id":   userID,
+			"full_name": displayName(ctx, userID),
+		})
 	}
 
+	webhookEvent := models.WebhookInviteDeclined
+	if accept {
+		webhookEvent = models.WebhookInviteAccepted
+	}
+	GetWebhookService().Dispatch(invite.ProjectID, userID, webhookEvent, map[string]any{
+		"invite_id":   invite.ID,
+		"receiver_id": userID,
+		"status":      invite.Status,
+	})
+
+	event, verb := models.EventInviteDeclined, "declined"
+	if accept {
+		event, verb = models.EventInviteAccepted, "accepted"
+	}
+	var project models.Project
+	if err := database.DB.Collection("projects").FindOne(ctx, bson.M{"_id": invite.ProjectID}).Decode(&project); err != nil {
+		project.Name = "your project"
+	}
+	responder := displayName(ctx, userID)
+	GetNotificationService().Notify(Notification{
+		UserID:    invite.SenderID,
+		ActorID:   userID,
+		ProjectID: invite.ProjectID,
+		Event:     event,
+		Title:     fmt.Sprintf("%s %s your invite", responder, verb),
+		Message:   fmt.Sprintf("%s %s your invite to %s.", responder, verb, project.Name),
+		Link:      projectLink(invite.ProjectID),
+	})
+
 	return &invite, nil
 }
 
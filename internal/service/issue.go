@@ -4,8 +4,11 @@ import (
 	"context"
 	"fmt"
 	"managify/database"
+	"managify/dto/request"
 
+	"managify/internal/realtime"
 	"managify/models"
+	"slices"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -50,6 +53,9 @@ func (s *IssueService) CreateIssue(issue *models.Issue, userID primitive.ObjectI
 	if !isProjectValid {
 		return nil, fmt.Errorf("project is not valid")
 	}
+	if err := GetProjectService().EnsureWritable(issue.ProjectID); err != nil {
+		return nil, err
+	}
 
 	// User validation
 	isUserInProject, err := GetProjectService().IsUserInProject(userID, issue.ProjectID)
@@ -60,13 +66,57 @@ func (s *IssueService) CreateIssue(issue *models.Issue, userID primitive.ObjectI
 		return nil, fmt.Errorf("user is not in project")
 	}
 
+	// Assignee validation
+	if !issue.AssigneeID.IsZero() {
+		isAssigneeInProject, err := GetProjectService().IsUserInProject(issue.AssigneeID, issue.ProjectID)
+		if err != nil {
+			return nil, err
+		}
+		if !isAssigneeInProject {
+			return nil, fmt.Errorf("assignee is not part of the project team")
+		}
+	}
+
+	// Status validation
+	statusColl := database.DB.Collection(GetStatusService().Collection)
+	if !issue.StatusID.IsZero() {
+		count, err := statusColl.CountDocuments(ctx, bson.M{"_id": issue.StatusID, "project_id": issue.ProjectID})
+		if err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("status is not part of the project")
+		}
+	}
+
 	issue.ID = primitive.NewObjectID()
+	issue.UpdatedAt = time.Now()
+	issue.DueReminderFor = ""
+	issue.WatcherIDs = []primitive.ObjectID{userID}
+	if !issue.AssigneeID.IsZero() && issue.AssigneeID != userID {
+		issue.WatcherIDs = append(issue.WatcherIDs, issue.AssigneeID)
+	}
 
 	if _, err := collection.InsertOne(ctx, issue); err != nil {
 		log.Errorf("Failed to insert issue into DB: %v", err)
 		return nil, err
 	}
 
+	if !issue.StatusID.IsZero() {
+		if _, err := statusColl.UpdateOne(ctx, bson.M{"_id": issue.StatusID}, bson.M{"$push": bson.M{"issues": issue.ID}}); err != nil {
+			log.Errorf("Failed to add issue to status: %v", err)
+			return nil, err
+		}
+	}
+
+	if !issue.AssigneeID.IsZero() {
+		usersColl := database.DB.Collection(GetUserService().Collection)
+		if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": issue.AssigneeID}, bson.M{"$addToSet": bson.M{"assigned_issues": issue.ID}}); err != nil {
+			log.Errorf("Failed to link issue to assignee: %v", err)
+			return nil, err
+		}
+	}
+
 	projectLogId := primitive.NewObjectID()
 	projectLog := models.ProjectLog{
 		ID:        projectLogId,
@@ -79,6 +129,12 @@ func (s *IssueService) CreateIssue(issue *models.Issue, userID primitive.ObjectI
 		return nil, err
 	}
 
+	publishEvent(issue.ProjectID, userID, realtime.IssueCreated, issue)
+	GetNotificationService().notifyIssueCreated(ctx, issue, userID)
+	if !issue.AssigneeID.IsZero() {
+		GetNotificationService().notifyAssigned(ctx, issue, userID)
+	}
+
 	return issue, nil
 }
 func (s *IssueService) DeleteIssue(issueID, userID primitive.ObjectID) error {
@@ -102,12 +158,43 @@ func (s *IssueService) DeleteIssue(issueID, userID primitive.ObjectID) error {
 	if !isUserInProject {
 		return fmt.Errorf("user is not allowed to delete this issue")
 	}
+	if err := projectService.EnsureWritable(issue.ProjectID); err != nil {
+		return err
+	}
 
 	_, err = collection.DeleteOne(ctx, bson.M{"_id": issueID})
 	if err != nil {
 		log.Errorf("Failed to delete issue from DB: %v", err)
 		return err
 	}
+
+	if !issue.StatusID.IsZero() {
+		statusColl := database.DB.Collection(GetStatusService().Collection)
+		if _, err := statusColl.UpdateOne(ctx, bson.M{"_id": issue.StatusID}, bson.M{"$pull": bson.M{"issues": issueID}}); err != nil {
+			log.Errorf("Failed to remove issue from status: %v", err)
+			return err
+		}
+	}
+
+	if !issue.AssigneeID.IsZero() {
+		usersColl := database.DB.Collection(GetUserService().Collection)
+		if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": issue.AssigneeID}, bson.M{"$pull": bson.M{"assigned_issues": issueID}}); err != nil {
+			log.Errorf("Failed to unlink issue from assignee: %v", err)
+			return err
+		}
+	}
+
+	if err := GetCommentService().DeleteCommentsByIssueID(issueID); err != nil {
+		return err
+	}
+	if err := GetIssueHistoryService().DeleteHistoryByIssueID(issueID); err != nil {
+		return err
+	}
+
+	publishEvent(issue.ProjectID, userID, realtime.IssueDeleted, map[string]any{
+		"issue_id":  issueID,
+		"status_id": issue.StatusID,
+	})
 	return nil
 }
 func (s *IssueService) GetIssuesByStatusID(statusID primitive.ObjectID) ([]*models.Issue, error) {
@@ -132,47 +219,133 @@ func (s *IssueService) GetIssuesByStatusID(statusID primitive.ObjectID) ([]*mode
 
 	return issues, nil
 }
-func (s *IssueService) UpdateIssueStatus(issueID, newStatusID, userID primitive.ObjectID) (*models.Issue, error) {
+
+// UpdateIssueStatus moves an issue to the bottom of another status column.
+// resolution is only needed when the workflow requires one.
+func (s *IssueService) UpdateIssueStatus(issueID, newStatusID, userID primitive.ObjectID, resolution string) (*models.Issue, error) {
+	return GetBoardService().MoveIssue(issueID, userID, IssueMove{StatusID: newStatusID, Resolution: resolution})
+}
+
+// UpdateIssue applies a partial update and records a before/after entry for
+// every field that actually changed.
+func (s *IssueService) UpdateIssue(issueID, userID primitive.ObjectID, req *request.IssueUpdateRequest) (*models.Issue, error) {
+	issue, err := s.GetIssueById(issueID)
+	if err != nil {
+		return nil, err
+	}
+
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, issue.ProjectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+	if err := GetProjectService().EnsureWritable(issue.ProjectID); err != nil {
+		return nil, err
+	}
+	if req.Status != nil {
+		return nil, fmt.Errorf("status cannot be changed here, move the issue on the board instead")
+	}
+
+	set := bson.M{}
+	var changes []models.FieldChange
+
+	if req.Title != nil && *req.Title != issue.Title {
+		changes = append(changes, models.FieldChange{Field: "title", Before: issue.Title, After: *req.Title})
+		set["title"] = *req.Title
+		issue.Title = *req.Title
+	}
+	if req.Description != nil && *req.Description != issue.Description {
+		changes = append(changes, models.FieldChange{Field: "description", Before: issue.Description, After: *req.Description})
+		set["description"] = *req.Description
+		issue.Description = *req.Description
+	}
+	if req.Priority != nil {
+		priority := models.PriorityType(*req.Priority)
+		if !priority.IsValid() {
+			return nil, fmt.Errorf("invalid priority: %s", *req.Priority)
+		}
+		if priority != issue.Priority {
+			changes = append(changes, models.FieldChange{Field: "priority", Before: issue.Priority, After: priority})
+			set["priority"] = priority
+			issue.Priority = priority
+		}
+	}
+	if req.DueDate != nil && *req.DueDate != issue.DueDate {
+		if *req.DueDate != "" {
+			if _, err := time.Parse("2006-01-02", *req.DueDate); err != nil {
+				return nil, fmt.Errorf("due_date must be in YYYY-MM-DD format")
+			}
+		}
+		changes = append(changes, models.FieldChange{Field: "due_date", Before: issue.DueDate, After: *req.DueDate})
+		set["due_date"] = *req.DueDate
+		issue.DueDate = *req.DueDate
+	}
+	if req.Tags != nil && !slices.Equal(*req.Tags, issue.Tags) {
+		changes = append(changes, models.FieldChange{Field: "tags", Before: issue.Tags, After: *req.Tags})
+		set["tags"] = *req.Tags
+		issue.Tags = *req.Tags
+	}
+
+	if len(changes) == 0 {
+		return issue, nil
+	}
+
+	issue.UpdatedAt = time.Now()
+	set["updated_at"] = issue.UpdatedAt
+
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var issue models.Issue
-	if err := collection.FindOne(ctx, bson.M{"_id": issueID}).Decode(&issue); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("issue not found")
-		}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": issueID}, bson.M{"$set": set}); err != nil {
+		log.Errorf("Failed to update issue: %v", err)
 		return nil, err
 	}
 
-	update := bson.M{
-		"$set": bson.M{
-			"status_id":  newStatusID,
-			"updated_at": time.Now(),
-		},
+	history := models.IssueHistory{
+		IssueID:   issue.ID,
+		ProjectID: issue.ProjectID,
+		UserID:    userID,
+		Changes:   changes,
 	}
-	res, err := collection.UpdateOne(ctx, bson.M{"_id": issueID}, update)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update issue status: %w", err)
-	}
-	if res.MatchedCount == 0 {
-		return nil, fmt.Errorf("no matching issue found to update")
+	if err := GetIssueHistoryService().CreateHistory(&history); err != nil {
+		return nil, err
 	}
 
 	projectLog := models.ProjectLog{
 		ID:        primitive.NewObjectID(),
 		ProjectID: issue.ProjectID.Hex(),
 		UserID:    userID.Hex(),
-		Message:   fmt.Sprintf("Issue '%s' status changed to new status", issue.Title),
+		Message:   fmt.Sprintf("Issue '%s' updated: %s", issue.Title, describeChanges(changes)),
 		Timestamp: time.Now(),
 	}
 	if err := GetLogService().CreateLog(&projectLog); err != nil {
 		return nil, err
 	}
 
-	issue.StatusID = newStatusID
+	publishEvent(issue.ProjectID, userID, realtime.IssueUpdated, map[string]any{
+		"issue":   issue,
+		"changes": changes,
+	})
+	return issue, nil
+}
 
-	return &issue, nil
+func (s *IssueService) statusName(statusID primitive.ObjectID) string {
+	if statusID.IsZero() {
+		return "none"
+	}
+
+	collection := database.DB.Collection(GetStatusService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var status models.Status
+	if err := collection.FindOne(ctx, bson.M{"_id": statusID}).Decode(&status); err != nil {
+		return statusID.Hex()
+	}
+	return status.Name
 }
 
 func (s *IssueService) GetOncomingIssues(projectID primitive.ObjectID) ([]*models.Issue, error) {
@@ -216,3 +389,19 @@ func (s *IssueService) GetOncomingIssues(projectID primitive.ObjectID) ([]*model
 
 	return issues, nil
 }
+
+func (s *IssueService) GetIssueById(issueID primitive.ObjectID) (*models.Issue, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var issue models.Issue
+	if err := collection.FindOne(ctx, bson.M{"_id": issueID}).Decode(&issue); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("issue not found")
+		}
+		return nil, err
+	}
+
+	return &issue, nil
+}
@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/models"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	defaultIssueQueryLimit = 20
+	maxIssueQueryLimit     = 100
+)
+
+// issueSortFields maps public sort keys to document fields and the value used
+// when the field is missing, so keyset pagination stays total.
+var issueSortFields = map[string]struct {
+	field    string
+	fallback interface{}
+}{
+	"created":  {field: "_id"},
+	"updated":  {field: "updated_at", fallback: time.Time{}},
+	"due_date": {field: "due_date", fallback: ""},
+	"title":    {field: "title", fallback: ""},
+}
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+type IssueQueryResult struct {
+	Issues     []*models.Issue `json:"issues"`
+	NextCursor string          `json:"next_cursor,omitempty"`
+}
+
+type issueCursor struct {
+	Value interface{} `json:"v,omitempty"`
+	ID    string      `json:"id"`
+}
+
+// QueryIssues filters the issues of a project and returns one page ordered by
+// the requested sort key. The returned cursor is opaque to clients.
+func (s *IssueService) QueryIssues(projectID, userID primitive.ObjectID, req *request.IssueQueryRequest) (*IssueQueryResult, error) {
+	isUserInProject, err := GetProjectService().IsUserInProject(userID, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !isUserInProject {
+		return nil, fmt.Errorf("user is not in project")
+	}
+
+	filter, err := buildIssueFilter(projectID, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	sortKey := req.Sort
+	if sortKey == "" {
+		sortKey = "created"
+	}
+	sortField, ok := issueSortFields[sortKey]
+	if !ok {
+		return nil, fmt.Errorf("invalid sort key: %s", req.Sort)
+	}
+
+	direction := -1
+	switch req.Order {
+	case "", "desc":
+	case "asc":
+		direction = 1
+	default:
+		return nil, fmt.Errorf("order must be asc or desc")
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultIssueQueryLimit
+	}
+	if limit > maxIssueQueryLimit {
+		limit = maxIssueQueryLimit
+	}
+
+	sortExpr := interface{}("$" + sortField.field)
+	if sortField.field != "_id" {
+		sortExpr = bson.M{"$ifNull": bson.A{"$" + sortField.field, sortField.fallback}}
+	}
+
+	pipeline := []bson.M{
+		{"$match": filter},
+		{"$addFields": bson.M{"_sort": sortExpr}},
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeIssueCursor(req.Cursor, sortKey)
+		if err != nil {
+			return nil, err
+		}
+		pipeline = append(pipeline, bson.M{"$match": after.condition(direction)})
+	}
+
+	pipeline = append(pipeline,
+		bson.M{"$sort": bson.D{{Key: "_sort", Value: direction}, {Key: "_id", Value: direction}}},
+		bson.M{"$limit": limit + 1},
+	)
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	type sortedIssue struct {
+		models.Issue `bson:",inline"`
+		Sort         interface{} `bson:"_sort"`
+	}
+	var rows []sortedIssue
+	if err := cursor.All(ctx, &rows); err != nil {
+		return nil, fmt.Errorf("decode issues failed: %w", err)
+	}
+
+	result := &IssueQueryResult{Issues: make([]*models.Issue, 0, len(rows))}
+	if len(rows) > limit {
+		last := rows[limit-1]
+		next, err := encodeIssueCursor(sortKey, last.Sort, last.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.NextCursor = next
+		rows = rows[:limit]
+	}
+	for i := range rows {
+		result.Issues = append(result.Issues, &rows[i].Issue)
+	}
+
+	return result, nil
+}
+
+func buildIssueFilter(projectID, userID primitive.ObjectID, req *request.IssueQueryRequest) (bson.M, error) {
+	filter := bson.M{"project_id": projectID}
+
+	if priorities := splitQueryValues(req.Priority); len(priorities) > 0 {
+		for _, p := range priorities {
+			if !models.PriorityType(p).IsValid() {
+				return nil, fmt.Errorf("invalid priority: %s", p)
+			}
+		}
+		filter["priority"] = bson.M{"$in": priorities}
+	}
+
+	if statuses := splitQueryValues(req.Status); len(statuses) > 0 {
+		for _, st := range statuses {
+			if !models.StatusType(st).IsValid() {
+				return nil, fmt.Errorf("invalid status: %s", st)
+			}
+		}
+		filter["status"] = bson.M{"$in": statuses}
+	}
+
+	if req.StatusID != "" {
+		statusID, err := primitive.ObjectIDFromHex(req.StatusID)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status_id")
+		}
+		filter["status_id"] = statusID
+	}
+
+	if tags := splitQueryValues(req.Tags); len(tags) > 0 {
+		filter["tags"] = bson.M{"$all": tags}
+	}
+
+	if req.DueFrom != "" || req.DueTo != "" {
+		due := bson.M{}
+		if req.DueFrom != "" {
+			if _, err := time.Parse("2006-01-02", req.DueFrom); err != nil {
+				return nil, fmt.Errorf("due_from must be in YYYY-MM-DD format")
+			}
+			due["$gte"] = req.DueFrom
+		}
+		if req.DueTo != "" {
+			if _, err := time.Parse("2006-01-02", req.DueTo); err != nil {
+				return nil, fmt.Errorf("due_to must be in YYYY-MM-DD format")
+			}
+			due["$lte"] = req.DueTo
+		}
+		filter["due_date"] = due
+	}
+
+	switch req.Assignee {
+	case "":
+	case "me":
+		filter["assignee_id"] = userID
+	case "none":
+		filter["assignee_id"] = bson.M{"$exists": false}
+	default:
+		assigneeID, err := primitive.ObjectIDFromHex(req.Assignee)
+		if err != nil {
+			return nil, fmt.Errorf("invalid assignee")
+		}
+		filter["assignee_id"] = assigneeID
+	}
+
+	if text := strings.TrimSpace(req.Text); text != "" {
+		pattern := primitive.Regex{Pattern: regexp.QuoteMeta(text), Options: "i"}
+		filter["$or"] = []bson.M{
+			{"title": pattern},
+			{"description": pattern},
+		}
+	}
+
+	return filter, nil
+}
+
+func splitQueryValues(values []string) []string {
+	var out []string
+	for _, v := range values {
+		for _, part := range strings.Split(v, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				out = append(out, part)
+			}
+		}
+	}
+	return out
+}
+
+func encodeIssueCursor(sortKey string, value interface{}, id primitive.ObjectID) (string, error) {
+	c := issueCursor{ID: id.Hex()}
+	switch v := value.(type) {
+	case primitive.DateTime:
+		c.Value = v.Time().UTC().Format(time.RFC3339Nano)
+	case string:
+		c.Value = v
+	}
+	if sortKey == "created" {
+		c.Value = nil
+	}
+
+	raw, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+type decodedIssueCursor struct {
+	value interface{}
+	id    primitive.ObjectID
+	byID  bool
+}
+
+func decodeIssueCursor(token, sortKey string) (*decodedIssueCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	var c issueCursor
+	if err := json.Unmarshal(raw, &c); err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	id, err := primitive.ObjectIDFromHex(c.ID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+
+	decoded := &decodedIssueCursor{id: id, byID: sortKey == "created"}
+	if decoded.byID {
+		return decoded, nil
+	}
+
+	str, ok := c.Value.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid cursor")
+	}
+	if sortKey == "updated" {
+		t, err := time.Parse(time.RFC3339Nano, str)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		decoded.value = t
+	} else {
+		decoded.value = str
+	}
+
+	return decoded, nil
+}
+
+// condition selects the documents that come strictly after the cursor in the
+// (sort value, _id) order.
+func (c *decodedIssueCursor) condition(direction int) bson.M {
+	op := "$lt"
+	if direction > 0 {
+		op = "$gt"
+	}
+
+	if c.byID {
+		return bson.M{"_id": bson.M{op: c.id}}
+	}
+
+	return bson.M{"$or": []bson.M{
+		{"_sort": bson.M{op: c.value}},
+		{"_sort": c.value, "_id": bson.M{op: c.id}},
+	}}
+}
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"managify/dto/request"
+	"managify/models"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUpdateIssueRejectsStatus(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		userID := primitive.NewObjectID()
+		project := models.Project{ID: primitive.NewObjectID(), OwnerID: userID}
+		issue := models.Issue{ID: primitive.NewObjectID(), ProjectID: project.ID, Status: models.TODO}
+
+		mt.AddMockResponses(
+			docsReply(t, issue),
+			countReply(t, 1),
+			docsReply(t, project),
+		)
+
+		status := "DONE"
+		_, err := GetIssueService().UpdateIssue(issue.ID, userID, &request.IssueUpdateRequest{Status: &status})
+		if err == nil {
+			t.Fatal("expected status in PATCH to be rejected")
+		}
+		if slices.Contains(sentCommands(mt), "update") {
+			t.Error("issue was updated")
+		}
+	})
+}
@@ -0,0 +1,381 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const loginAttemptsCollection = "login_attempts"
+
+// AttemptStore keeps the failed login counters used by LoginGuard.
+type AttemptStore interface {
+	// Get returns the counter of key, or nil if there is none.
+	Get(ctx context.Context, key string) (*models.LoginAttempt, error)
+	// RecordFailure counts a failure at now. Failures older than window
+	// are forgotten first.
+	RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error)
+	// Lock blocks key until the given time.
+	Lock(ctx context.Context, key string, until time.Time) error
+	// Reset forgets every failure and lock of key.
+	Reset(ctx context.Context, key string) error
+}
+
+// LockoutPolicy configures LoginGuard.
+type LockoutPolicy struct {
+	// MaxAccountFailures and MaxIPFailures failures within Window lock the
+	// account or IP address for LockoutDuration.
+	MaxAccountFailures int
+	MaxIPFailures      int
+	Window             time.Duration
+	LockoutDuration    time.Duration
+	// After DelayAfter failures each further attempt has to wait BaseDelay,
+	// doubling with every failure up to MaxDelay.
+	DelayAfter int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// LoginBlockedError is returned while an account or IP address has to wait
+// before the next login attempt.
+type LoginBlockedError struct {
+	RetryAfter time.Duration
+	Locked     bool
+}
+
+func (e *LoginBlockedError) Error() string {
+	seconds := int(e.RetryAfter.Round(time.Second).Seconds())
+	if e.Locked {
+		return fmt.Sprintf("too many failed logins, try again in %d seconds", seconds)
+	}
+	return fmt.Sprintf("wait %d seconds before the next login attempt", seconds)
+}
+
+// LoginGuard slows down and temporarily locks logins after repeated failures
+// of the same account or from the same IP address.
+type LoginGuard struct {
+	Store  AttemptStore
+	Policy LockoutPolicy
+	Now    func() time.Time
+}
+
+var loginGuard *LoginGuard
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func NewLoginGuard(store AttemptStore, policy LockoutPolicy) *LoginGuard {
+	return &LoginGuard{Store: store, Policy: policy, Now: time.Now}
+}
+
+func GetLoginGuard() *LoginGuard {
+	if loginGuard == nil {
+		loginGuard = NewLoginGuard(NewMongoAttemptStore(), DefaultLockoutPolicy())
+	}
+	return loginGuard
+}
+
+// DefaultLockoutPolicy reads LOGIN_MAX_FAILURES (5), LOGIN_MAX_IP_FAILURES
+// (50), LOGIN_FAILURE_WINDOW_MINUTES (15) and LOGIN_LOCKOUT_MINUTES (15).
+func DefaultLockoutPolicy() LockoutPolicy {
+	return LockoutPolicy{
+		MaxAccountFailures: envInt("LOGIN_MAX_FAILURES", 5),
+		MaxIPFailures:      envInt("LOGIN_MAX_IP_FAILURES", 50),
+		Window:             time.Duration(envInt("LOGIN_FAILURE_WINDOW_MINUTES", 15)) * time.Minute,
+		LockoutDuration:    time.Duration(envInt("LOGIN_LOCKOUT_MINUTES", 15)) * time.Minute,
+		DelayAfter:         3,
+		BaseDelay:          time.Second,
+		MaxDelay:           30 * time.Second,
+	}
+}
+
+func envInt(name string, def int) int {
+	v, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || v <= 0 {
+		return def
+	}
+	return v
+}
+
+func accountKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipKey(ip string) string {
+	return "ip:" + ip
+}
+
+// Check returns a *LoginBlockedError if a login for email from ip has to wait.
+func (g *LoginGuard) Check(ctx context.Context, email, ip string) error {
+	now := g.Now()
+
+	var blocked *LoginBlockedError
+	for _, key := range g.keys(email, ip) {
+		attempt, err := g.Store.Get(ctx, key)
+		if err != nil {
+			return err
+		}
+		if attempt == nil {
+			continue
+		}
+
+		var wait time.Duration
+		locked := attempt.LockedUntil != nil && attempt.LockedUntil.After(now)
+		if locked {
+			wait = attempt.LockedUntil.Sub(now)
+		} else if now.Sub(attempt.LastFailure) < g.Policy.Window {
+			wait = attempt.LastFailure.Add(g.delay(attempt.Failures)).Sub(now)
+		}
+		if wait > 0 && (blocked == nil || wait > blocked.RetryAfter) {
+			blocked = &LoginBlockedError{RetryAfter: wait, Locked: locked}
+		}
+	}
+
+	if blocked != nil {
+		return blocked
+	}
+	return nil
+}
+
+// Failure counts a failed login for email from ip and locks the account or the
+// address once its limit is reached. userID is zero for unknown emails.
+func (g *LoginGuard) Failure(ctx context.Context, email, ip string, userID primitive.ObjectID) {
+	now := g.Now()
+
+	limits := map[string]int{accountKey(email): g.Policy.MaxAccountFailures}
+	if ip != "" {
+		limits[ipKey(ip)] = g.Policy.MaxIPFailures
+	}
+
+	for key, limit := range limits {
+		attempt, err := g.Store.RecordFailure(ctx, key, now, g.Policy.Window)
+		if err != nil {
+			log.WithError(err).Errorf("failed to record login failure for %s", key)
+			continue
+		}
+		if attempt.Failures < limit || (attempt.LockedUntil != nil && attempt.LockedUntil.After(now)) {
+			continue
+		}
+
+		until := now.Add(g.Policy.LockoutDuration)
+		if err := g.Store.Lock(ctx, key, until); err != nil {
+			log.WithError(err).Errorf("failed to lock %s", key)
+			continue
+		}
+
+		entry := &models.AuditLog{
+			Action:    models.AuditLoginLocked,
+			Message:   fmt.Sprintf("Locked after %d failed logins until %s", attempt.Failures, until.Format(time.RFC1123)),
+			Timestamp: now,
+		}
+		if strings.HasPrefix(key, "ip:") {
+			entry.IP = ip
+		} else {
+			entry.Email = email
+			entry.UserID = userID
+			entry.IP = ip
+		}
+		GetAuditService().Record(entry)
+		log.Warnf("Login locked for %s until %s", key, until)
+	}
+}
+
+// Success forgets the failed logins of the account. Failures of the IP address
+// are kept so one valid account cannot be used to reset them.
+func (g *LoginGuard) Success(ctx context.Context, email string) {
+	if err := g.Store.Reset(ctx, accountKey(email)); err != nil {
+		log.WithError(err).Error("failed to reset login failures")
+	}
+}
+
+// Unlock lifts the lockout of a user's account.
+func (g *LoginGuard) Unlock(actorID primitive.ObjectID, user *models.User) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := g.Store.Reset(ctx, accountKey(user.Email)); err != nil {
+		return err
+	}
+
+	GetAuditService().Record(&models.AuditLog{
+		Action:  models.AuditLoginUnlocked,
+		ActorID: actorID,
+		UserID:  user.ID,
+		Email:   user.Email,
+		Message: "Login unlocked by an admin",
+	})
+	return nil
+}
+
+func (g *LoginGuard) keys(email, ip string) []string {
+	keys := []string{accountKey(email)}
+	if ip != "" {
+		keys = append(keys, ipKey(ip))
+	}
+	return keys
+}
+
+// delay is how long to wait after the given number of failures.
+func (g *LoginGuard) delay(failures int) time.Duration {
+	if failures < g.Policy.DelayAfter || g.Policy.BaseDelay <= 0 {
+		return 0
+	}
+	d := g.Policy.BaseDelay
+	for i := g.Policy.DelayAfter; i < failures && d < g.Policy.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, g.Policy.MaxDelay)
+}
+
+// MemoryAttemptStore keeps counters in process memory. It suits a single
+// instance and tests.
+type MemoryAttemptStore struct {
+	mu       sync.Mutex
+	attempts map[string]*models.LoginAttempt
+}
+
+func NewMemoryAttemptStore() *MemoryAttemptStore {
+	return &MemoryAttemptStore{attempts: make(map[string]*models.LoginAttempt)}
+}
+
+func (m *MemoryAttemptStore) Get(_ context.Context, key string) (*models.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		return nil, nil
+	}
+	copied := *attempt
+	return &copied, nil
+}
+
+func (m *MemoryAttemptStore) RecordFailure(_ context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.prune(now)
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		attempt = &models.LoginAttempt{Key: key}
+		m.attempts[key] = attempt
+	}
+	if now.Sub(attempt.LastFailure) >= window {
+		attempt.Failures = 0
+	}
+	attempt.Failures++
+	attempt.LastFailure = now
+	attempt.ExpiresAt = now.Add(window)
+	if attempt.LockedUntil != nil && attempt.LockedUntil.After(attempt.ExpiresAt) {
+		attempt.ExpiresAt = *attempt.LockedUntil
+	}
+
+	copied := *attempt
+	return &copied, nil
+}
+
+func (m *MemoryAttemptStore) Lock(_ context.Context, key string, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	attempt, ok := m.attempts[key]
+	if !ok {
+		attempt = &models.LoginAttempt{Key: key}
+		m.attempts[key] = attempt
+	}
+	attempt.LockedUntil = &until
+	if until.After(attempt.ExpiresAt) {
+		attempt.ExpiresAt = until
+	}
+	return nil
+}
+
+func (m *MemoryAttemptStore) Reset(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.attempts, key)
+	return nil
+}
+
+// prune drops expired counters. Callers hold m.mu.
+func (m *MemoryAttemptStore) prune(now time.Time) {
+	for key, attempt := range m.attempts {
+		if now.After(attempt.ExpiresAt) {
+			delete(m.attempts, key)
+		}
+	}
+}
+
+// MongoAttemptStore shares counters between instances. Expired counters are
+// removed by a TTL index on expires_at.
+type MongoAttemptStore struct {
+	Collection string
+}
+
+func NewMongoAttemptStore() *MongoAttemptStore {
+	return &MongoAttemptStore{Collection: loginAttemptsCollection}
+}
+
+func (s *MongoAttemptStore) Get(ctx context.Context, key string) (*models.LoginAttempt, error) {
+	var attempt models.LoginAttempt
+	if err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": key}).Decode(&attempt); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (s *MongoAttemptStore) RecordFailure(ctx context.Context, key string, now time.Time, window time.Duration) (*models.LoginAttempt, error) {
+	// A pipeline update so the window check and the increment are atomic.
+	update := mongo.Pipeline{{{Key: "$set", Value: bson.M{
+		"failures": bson.M{"$cond": bson.A{
+			bson.M{"$gt": bson.A{"$last_failure", now.Add(-window)}},
+			bson.M{"$add": bson.A{"$failures", 1}},
+			1,
+		}},
+		"last_failure": now,
+		"expires_at":   bson.M{"$max": bson.A{now.Add(window), "$locked_until"}},
+	}}}}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var attempt models.LoginAttempt
+	if err := database.DB.Collection(s.Collection).FindOneAndUpdate(ctx, bson.M{"_id": key}, update, opts).Decode(&attempt); err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (s *MongoAttemptStore) Lock(ctx context.Context, key string, until time.Time) error {
+	update := bson.M{
+		"$set": bson.M{"locked_until": until},
+		"$max": bson.M{"expires_at": until},
+	}
+	_, err := database.DB.Collection(s.Collection).UpdateOne(ctx, bson.M{"_id": key}, update, options.Update().SetUpsert(true))
+	return err
+}
+
+func (s *MongoAttemptStore) Reset(ctx context.Context, key string) error {
+	_, err := database.DB.Collection(s.Collection).DeleteOne(ctx, bson.M{"_id": key})
+	return err
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"managify/models"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const (
+	guardEmail = "Jane@Example.com"
+	guardIP    = "203.0.113.7"
+)
+
+// newTestGuard returns a guard with an in-memory store whose clock only moves
+// through the returned advance function.
+func newTestGuard() (*LoginGuard, *MemoryAttemptStore, func(time.Duration)) {
+	store := NewMemoryAttemptStore()
+	g := NewLoginGuard(store, LockoutPolicy{
+		MaxAccountFailures: 5,
+		MaxIPFailures:      50,
+		Window:             15 * time.Minute,
+		LockoutDuration:    15 * time.Minute,
+		DelayAfter:         3,
+		BaseDelay:          time.Second,
+		MaxDelay:           8 * time.Second,
+	})
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	g.Now = func() time.Time { return now }
+	return g, store, func(d time.Duration) { now = now.Add(d) }
+}
+
+func fail(g *LoginGuard, times int) {
+	for range times {
+		g.Failure(context.Background(), guardEmail, guardIP, primitive.NilObjectID)
+	}
+}
+
+func blocked(t *testing.T, err error) *LoginBlockedError {
+	t.Helper()
+	var b *LoginBlockedError
+	if !errors.As(err, &b) {
+		t.Fatalf("Check = %v, want *LoginBlockedError", err)
+	}
+	return b
+}
+
+func TestLoginGuardDelay(t *testing.T) {
+	g, _, _ := newTestGuard()
+
+	want := map[int]time.Duration{
+		0: 0, 2: 0,
+		3: time.Second, 4: 2 * time.Second, 5: 4 * time.Second, 6: 8 * time.Second,
+		7: 8 * time.Second, 40: 8 * time.Second,
+	}
+	for failures, d := range want {
+		if got := g.delay(failures); got != d {
+			t.Errorf("delay(%d) = %s, want %s", failures, got, d)
+		}
+	}
+}
+
+func TestLoginGuardCheckWaitsForDelay(t *testing.T) {
+	g, _, advance := newTestGuard()
+	ctx := context.Background()
+
+	fail(g, 2)
+	if err := g.Check(ctx, guardEmail, guardIP); err != nil {
+		t.Fatalf("after 2 failures: %v", err)
+	}
+
+	fail(g, 2)
+	b := blocked(t, g.Check(ctx, guardEmail, guardIP))
+	if b.Locked || b.RetryAfter != 2*time.Second {
+		t.Fatalf("after 4 failures: %+v, want a 2s delay", b)
+	}
+
+	advance(2 * time.Second)
+	if err := g.Check(ctx, guardEmail, guardIP); err != nil {
+		t.Fatalf("after the delay: %v", err)
+	}
+}
+
+func TestLoginGuardLockout(t *testing.T) {
+	g, store, advance := newTestGuard()
+	ctx := context.Background()
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		fail(g, 5)
+		b := blocked(t, g.Check(ctx, guardEmail, ""))
+		if !b.Locked || b.RetryAfter != 15*time.Minute {
+			t.Fatalf("after 5 failures: %+v, want locked for 15m", b)
+		}
+
+		e := mt.GetStartedEvent()
+		if e == nil || e.CommandName != "insert" {
+			t.Fatalf("lockout was not audited, got %v", e)
+		}
+		if action := e.Command.Lookup("documents", "0", "action").StringValue(); action != string(models.AuditLoginLocked) {
+			t.Errorf("audit action = %s", action)
+		}
+
+		// Further failures while locked do not extend the lock.
+		fail(g, 1)
+		if mt.GetStartedEvent() != nil {
+			t.Error("failure while locked locked again")
+		}
+		attempt, _ := store.Get(ctx, accountKey(guardEmail))
+		if !attempt.LockedUntil.Equal(g.Now().Add(15 * time.Minute)) {
+			t.Errorf("locked until %s", attempt.LockedUntil)
+		}
+	})
+
+	advance(15 * time.Minute)
+	if err := g.Check(ctx, guardEmail, ""); err != nil {
+		t.Fatalf("after the lockout: %v", err)
+	}
+}
+
+func TestLoginGuardWindowReset(t *testing.T) {
+	g, store, advance := newTestGuard()
+	ctx := context.Background()
+
+	fail(g, 4)
+	advance(15 * time.Minute)
+	if err := g.Check(ctx, guardEmail, guardIP); err != nil {
+		t.Fatalf("after the window: %v", err)
+	}
+
+	fail(g, 1)
+	for _, key := range []string{accountKey(guardEmail), ipKey(guardIP)} {
+		attempt, err := store.Get(ctx, key)
+		if err != nil || attempt == nil || attempt.Failures != 1 {
+			t.Errorf("%s: %+v, %v, want the count to restart at 1", key, attempt, err)
+		}
+	}
+}
+
+func TestLoginGuardSuccessKeepsIPFailures(t *testing.T) {
+	g, store, _ := newTestGuard()
+	ctx := context.Background()
+
+	fail(g, 3)
+	g.Success(ctx, "  jane@example.COM ")
+
+	if attempt, _ := store.Get(ctx, accountKey(guardEmail)); attempt != nil {
+		t.Errorf("account failures kept after success: %+v", attempt)
+	}
+	if attempt, _ := store.Get(ctx, ipKey(guardIP)); attempt == nil || attempt.Failures != 3 {
+		t.Errorf("ip failures = %+v, want 3", attempt)
+	}
+	if err := g.Check(ctx, guardEmail, ""); err != nil {
+		t.Errorf("account still blocked: %v", err)
+	}
+	blocked(t, g.Check(ctx, "other@example.com", guardIP))
+}
+
+func TestLoginGuardUnlock(t *testing.T) {
+	g, _, _ := newTestGuard()
+	ctx := context.Background()
+	user := &models.User{ID: primitive.NewObjectID(), Email: guardEmail}
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(), mtest.CreateSuccessResponse())
+
+		fail(g, 5)
+		if !blocked(t, g.Check(ctx, guardEmail, "")).Locked {
+			t.Fatal("account is not locked")
+		}
+
+		if err := g.Unlock(primitive.NewObjectID(), user); err != nil {
+			t.Fatal(err)
+		}
+		if err := g.Check(ctx, guardEmail, ""); err != nil {
+			t.Fatalf("after unlock: %v", err)
+		}
+
+		mt.GetStartedEvent()
+		e := mt.GetStartedEvent()
+		if e == nil || e.Command.Lookup("documents", "0", "action").StringValue() != string(models.AuditLoginUnlocked) {
+			t.Errorf("unlock was not audited, got %v", e)
+		}
+	})
+}
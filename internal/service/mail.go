@@ -0,0 +1,279 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"managify/database"
+	"managify/internal/mailer"
+	"managify/models"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultMailMaxAttempts = 5
+	// mailRetryBase is the delay before the first retry; it doubles with
+	// every further attempt up to mailRetryMax.
+	mailRetryBase = 30 * time.Second
+	mailRetryMax  = time.Hour
+	// mailSendTimeout bounds one delivery; a claimed email whose lock has
+	// passed is picked up again, e.g. after a crash.
+	mailSendTimeout = 30 * time.Second
+	mailLockTTL     = 2 * time.Minute
+	mailBatchSize   = 50
+)
+
+// MailService queues transactional emails in a persistent outbox, from which
+// StartMailOutbox delivers them through Mailer with retries. Without a Mailer
+// emails stay pending until one is configured.
+type MailService struct {
+	Collection string
+	Mailer     mailer.Mailer
+
+	wake chan struct{}
+}
+
+var mailService *MailService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetMailService() *MailService {
+	if mailService == nil {
+		m, err := mailer.FromEnv()
+		if err != nil {
+			log.WithError(err).Error("Emails are not delivered and stay pending in the outbox")
+		}
+		mailService = &MailService{
+			Collection: "email_outbox",
+			Mailer:     m,
+			wake:       make(chan struct{}, 1),
+		}
+	}
+	return mailService
+}
+
+// mailMaxAttempts reads MAIL_MAX_ATTEMPTS, defaulting to 5.
+func mailMaxAttempts() int {
+	return envInt("MAIL_MAX_ATTEMPTS", defaultMailMaxAttempts)
+}
+
+// Enqueue renders an email and stores it in the outbox. It is delivered in
+// the background right away, or retried later if that fails.
+func (s *MailService) Enqueue(ctx context.Context, to, template string, data map[string]any) error {
+	msg, err := mailer.Render(template, to, data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	email := models.OutboxEmail{
+		ID:            primitive.NewObjectID(),
+		To:            to,
+		Template:      template,
+		Subject:       msg.Subject,
+		HTML:          msg.HTML,
+		Text:          msg.Text,
+		Status:        models.EmailPending,
+		NextAttemptAt: now,
+		CreatedAt:     now,
+	}
+	if _, err := database.DB.Collection(s.Collection).InsertOne(ctx, email); err != nil {
+		log.WithError(err).Error("failed to queue email")
+		return err
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// DeliverDue sends the emails whose next attempt is due and returns how many
+// were handled.
+func (s *MailService) DeliverDue() (int, error) {
+	if s.Mailer == nil {
+		return 0, mailer.ErrNotConfigured
+	}
+	handled := 0
+	for handled < mailBatchSize {
+		email, err := s.claim()
+		if err != nil {
+			return handled, err
+		}
+		if email == nil {
+			break
+		}
+		s.deliver(email)
+		handled++
+	}
+	return handled, nil
+}
+
+// claim locks the next due email so concurrent workers do not send it twice.
+func (s *MailService) claim() (*models.OutboxEmail, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.EmailPending, "next_attempt_at": bson.M{"$lte": now}},
+		{"status": models.EmailSending, "locked_until": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": models.EmailSending, "locked_until": now.Add(mailLockTTL)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var email models.OutboxEmail
+	if err := database.DB.Collection(s.Collection).FindOneAndUpdate(ctx, filter, update, opts).Decode(&email); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &email, nil
+}
+
+// deliver sends a claimed email and records the outcome. Failed attempts are
+// retried with exponential backoff until mailMaxAttempts is reached.
+func (s *MailService) deliver(email *models.OutboxEmail) {
+	sendCtx, cancelSend := context.WithTimeout(context.Background(), mailSendTimeout)
+	sendErr := s.Mailer.Send(sendCtx, mailer.Message{
+		To:      email.To,
+		Subject: email.Subject,
+		HTML:    email.HTML,
+		Text:    email.Text,
+	})
+	cancelSend()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	var update bson.M
+	switch {
+	case sendErr == nil:
+		update = bson.M{
+			"$set":   bson.M{"status": models.EmailSent, "sent_at": now},
+			"$unset": bson.M{"html": "", "text": "", "locked_until": "", "last_error": ""},
+		}
+		log.Infof("Email %s sent to %s", email.Template, email.To)
+	case email.Attempts >= mailMaxAttempts():
+		unset := bson.M{"locked_until": ""}
+		// A failed email cannot be retried with a stale link, so the body
+		// is dropped and the user has to request a new one.
+		if mailer.HasOneTimeLink(email.Template) {
+			unset["html"] = ""
+			unset["text"] = ""
+		}
+		update = bson.M{
+			"$set":   bson.M{"status": models.EmailFailed, "last_error": sendErr.Error(), "failed_at": now},
+			"$unset": unset,
+		}
+		log.WithError(sendErr).Errorf("Email %s to %s failed after %d attempts", email.Template, email.To, email.Attempts)
+	default:
+		delay := mailRetryBase << (email.Attempts - 1)
+		if delay > mailRetryMax || delay <= 0 {
+			delay = mailRetryMax
+		}
+		update = bson.M{
+			"$set": bson.M{
+				"status":          models.EmailPending,
+				"last_error":      sendErr.Error(),
+				"next_attempt_at": now.Add(delay),
+			},
+			"$unset": bson.M{"locked_until": ""},
+		}
+		log.WithError(sendErr).Warnf("Email %s to %s failed, retrying in %s", email.Template, email.To, delay)
+	}
+
+	if _, err := database.DB.Collection(s.Collection).UpdateOne(ctx, bson.M{"_id": email.ID}, update); err != nil {
+		log.WithError(err).Error("failed to record email delivery")
+	}
+}
+
+// GetEmails lists outbox entries, newest first, optionally by status.
+func (s *MailService) GetEmails(status string, limit int64) ([]*models.OutboxEmail, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{}
+	if status != "" {
+		filter["status"] = status
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	emails := []*models.OutboxEmail{}
+	if err := cursor.All(ctx, &emails); err != nil {
+		return nil, err
+	}
+	return emails, nil
+}
+
+// RetryEmail queues a failed email again with a fresh set of attempts. Emails
+// whose one-time link was dropped cannot be retried.
+func (s *MailService) RetryEmail(id primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := database.DB.Collection(s.Collection).UpdateOne(ctx,
+		bson.M{"_id": id, "status": models.EmailFailed, "text": bson.M{"$exists": true}},
+		bson.M{
+			"$set":   bson.M{"status": models.EmailPending, "attempts": 0, "next_attempt_at": time.Now()},
+			"$unset": bson.M{"failed_at": ""},
+		},
+	)
+	if err != nil {
+		return err
+	}
+	if res.MatchedCount == 0 {
+		return fmt.Errorf("failed email not found or its content was removed")
+	}
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// StartMailOutbox delivers queued emails every interval and whenever one is
+// enqueued.
+func StartMailOutbox(interval time.Duration) {
+	s := GetMailService()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.wake:
+			}
+			// A missing mailer was reported on startup.
+			if _, err := s.DeliverDue(); err != nil && !errors.Is(err, mailer.ErrNotConfigured) {
+				log.WithError(err).Error("Email outbox delivery failed")
+			}
+		}
+	}()
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"managify/internal/mailer"
+	"managify/models"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func newTestMailService(m *mailer.MemoryMailer) *MailService {
+	return &MailService{Collection: "email_outbox", Mailer: m, wake: make(chan struct{}, 1)}
+}
+
+func outboxEmail(attempts int) *models.OutboxEmail {
+	return &models.OutboxEmail{
+		ID:       primitive.NewObjectID(),
+		To:       "jane@example.com",
+		Template: mailer.TemplatePasswordReset,
+		Subject:  "Reset your password",
+		Text:     "reset link",
+		Status:   models.EmailSending,
+		Attempts: attempts,
+	}
+}
+
+func TestEnqueueRendersIntoOutbox(t *testing.T) {
+	s := newTestMailService(&mailer.MemoryMailer{})
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		err := s.Enqueue(context.Background(), "jane@example.com", mailer.TemplatePasswordReset, map[string]any{
+			"Name": "Jane", "Link": "https://app.example.com/reset?token=t", "Minutes": 30,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		doc := mt.GetStartedEvent().Command.Lookup("documents", "0").Document()
+		if doc.Lookup("status").StringValue() != string(models.EmailPending) ||
+			doc.Lookup("subject").StringValue() != "Reset your password" {
+			t.Errorf("queued %s", doc)
+		}
+		select {
+		case <-s.wake:
+		default:
+			t.Error("outbox was not woken up")
+		}
+	})
+}
+
+func TestDeliverSendsAndDropsBodies(t *testing.T) {
+	m := &mailer.MemoryMailer{}
+	s := newTestMailService(m)
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(updateReply(1))
+		s.deliver(outboxEmail(1))
+
+		if sent := m.Sent(); len(sent) != 1 || sent[0].To != "jane@example.com" {
+			t.Fatalf("sent = %+v", sent)
+		}
+		_, u := sentUpdate(t, mt)
+		if u.Lookup("$set", "status").StringValue() != string(models.EmailSent) {
+			t.Errorf("update %s does not mark the email sent", u)
+		}
+		if _, err := u.LookupErr("$unset", "html"); err != nil {
+			t.Errorf("update %s keeps the body with its link", u)
+		}
+	})
+}
+
+func TestDeliverRetriesWithBackoff(t *testing.T) {
+	m := &mailer.MemoryMailer{Err: errors.New("connection refused")}
+	s := newTestMailService(m)
+
+	withMockDB(t, func(mt *mtest.T) {
+		for attempts, delay := range map[int]time.Duration{
+			1: 30 * time.Second,
+			2: time.Minute,
+			3: 2 * time.Minute,
+		} {
+			mt.AddMockResponses(updateReply(1))
+			// BSON dates have millisecond precision.
+			before := time.Now().Truncate(time.Millisecond)
+			s.deliver(outboxEmail(attempts))
+
+			_, u := sentUpdate(t, mt)
+			set := u.Lookup("$set").Document()
+			if set.Lookup("status").StringValue() != string(models.EmailPending) ||
+				set.Lookup("last_error").StringValue() != "connection refused" {
+				t.Errorf("attempt %d: update %s", attempts, u)
+			}
+			next := set.Lookup("next_attempt_at").Time()
+			if got := next.Sub(before); got < delay || got > delay+time.Second {
+				t.Errorf("attempt %d: retried after %s, want %s", attempts, got, delay)
+			}
+		}
+		if len(m.Sent()) != 0 {
+			t.Error("failed sends were recorded as sent")
+		}
+	})
+}
+
+func TestDeliverGivesUpAfterMaxAttempts(t *testing.T) {
+	t.Setenv("MAIL_MAX_ATTEMPTS", "3")
+	s := newTestMailService(&mailer.MemoryMailer{Err: errors.New("mailbox unavailable")})
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(updateReply(1))
+		s.deliver(outboxEmail(3))
+
+		_, u := sentUpdate(t, mt)
+		if u.Lookup("$set", "status").StringValue() != string(models.EmailFailed) {
+			t.Errorf("update %s does not fail the email", u)
+		}
+		if _, err := u.LookupErr("$set", "failed_at"); err != nil {
+			t.Errorf("update %s leaves the email out of the retention TTL", u)
+		}
+		if _, err := u.LookupErr("$unset", "text"); err != nil {
+			t.Errorf("update %s keeps the reset link of a failed email", u)
+		}
+	})
+}
+
+func TestDeliverKeepsNotificationBodyOnFailure(t *testing.T) {
+	t.Setenv("MAIL_MAX_ATTEMPTS", "1")
+	s := newTestMailService(&mailer.MemoryMailer{Err: errors.New("mailbox unavailable")})
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(updateReply(1))
+		email := outboxEmail(1)
+		email.Template = mailer.TemplateNotification
+		s.deliver(email)
+
+		_, u := sentUpdate(t, mt)
+		if _, err := u.LookupErr("$unset", "text"); err == nil {
+			t.Errorf("update %s drops a body that can still be retried", u)
+		}
+	})
+}
+
+func TestRetryEmailResetsAttempts(t *testing.T) {
+	s := newTestMailService(&mailer.MemoryMailer{})
+	id := primitive.NewObjectID()
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(updateReply(1))
+		if err := s.RetryEmail(id); err != nil {
+			t.Fatal(err)
+		}
+		q, u := sentUpdate(t, mt)
+		if q.Lookup("status").StringValue() != string(models.EmailFailed) {
+			t.Errorf("filter %s retries emails that have not failed", q)
+		}
+		if !q.Lookup("text", "$exists").Boolean() {
+			t.Errorf("filter %s retries emails without a body", q)
+		}
+		if _, err := u.LookupErr("$unset", "failed_at"); err != nil {
+			t.Errorf("update %s lets the retention TTL delete the retried email", u)
+		}
+		if u.Lookup("$set", "attempts").AsInt64() != 0 || u.Lookup("$set", "status").StringValue() != string(models.EmailPending) {
+			t.Errorf("update %s", u)
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}))
+		if err := s.RetryEmail(id); err == nil {
+			t.Error("retried an email that is not failed")
+		}
+	})
+}
+
+func TestDeliverDueWithoutMailerKeepsEmailsPending(t *testing.T) {
+	s := &MailService{Collection: "email_outbox", wake: make(chan struct{}, 1)}
+
+	withMockDB(t, func(mt *mtest.T) {
+		n, err := s.DeliverDue()
+		if n != 0 || !errors.Is(err, mailer.ErrNotConfigured) {
+			t.Fatalf("DeliverDue = %d, %v, want ErrNotConfigured", n, err)
+		}
+		if cmds := sentCommands(mt); len(cmds) != 0 {
+			t.Errorf("claimed emails without a mailer: %v", cmds)
+		}
+	})
+}
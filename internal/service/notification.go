@@ -0,0 +1,545 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/internal/mailer"
+	"managify/models"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultDigestHour   = 8
+	defaultDueSoonDays  = 1
+	notificationTimeout = 10 * time.Second
+)
+
+// Notification is a single event for one recipient. Notifications the actor
+// causes for themselves are dropped.
+type Notification struct {
+	UserID    primitive.ObjectID
+	ActorID   primitive.ObjectID
+	ProjectID primitive.ObjectID
+	Event     models.NotificationEvent
+	Title     string
+	Message   string
+	Link      string
+}
+
+// NotificationService tells users about events that concern them. Every
+// notification lands in the in-app inbox; emailable events are also mailed,
+// either instantly or batched into hourly or daily digests kept in
+// Collection.
+type NotificationService struct {
+	Collection string
+}
+
+var notificationService *NotificationService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetNotificationService() *NotificationService {
+	if notificationService == nil {
+		notificationService = &NotificationService{Collection: "notification_queue"}
+	}
+	return notificationService
+}
+
+// digestHour reads NOTIFICATION_DIGEST_HOUR, the UTC hour daily digests are
+// sent at, defaulting to 8.
+func digestHour() int {
+	hour, err := strconv.Atoi(os.Getenv("NOTIFICATION_DIGEST_HOUR"))
+	if err != nil || hour < 0 || hour > 23 {
+		return defaultDigestHour
+	}
+	return hour
+}
+
+// nextDigestAt returns when a notification queued at now is sent.
+func nextDigestAt(now time.Time, frequency models.DigestFrequency) time.Time {
+	now = now.UTC()
+	if frequency == models.DigestHourly {
+		return now.Truncate(time.Hour).Add(time.Hour)
+	}
+	at := time.Date(now.Year(), now.Month(), now.Day(), digestHour(), 0, 0, 0, time.UTC)
+	if !at.After(now) {
+		at = at.AddDate(0, 0, 1)
+	}
+	return at
+}
+
+// Notify delivers the notifications in the background so the request that
+// caused them is not slowed down or failed by email problems.
+func (s *NotificationService) Notify(notifications ...Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), notificationTimeout)
+		defer cancel()
+
+		for _, n := range notifications {
+			if err := s.dispatch(ctx, n); err != nil {
+				log.WithError(err).Errorf("failed to notify user %s about %s", n.UserID.Hex(), n.Event)
+			}
+		}
+	}()
+}
+
+// dispatch adds the notification to the recipient's inbox and applies their
+// email preferences: muted events are not mailed, the rest are emailed now
+// or queued for the next digest.
+func (s *NotificationService) dispatch(ctx context.Context, n Notification) error {
+	if n.UserID.IsZero() || n.UserID == n.ActorID {
+		return nil
+	}
+
+	if err := GetInboxService().add(ctx, n); err != nil {
+		log.WithError(err).Errorf("failed to add notification to inbox of user %s", n.UserID.Hex())
+	}
+	if !n.Event.IsValid() {
+		return nil
+	}
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"full_name": 1, "email": 1, "notification_prefs": 1})
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": n.UserID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+
+	prefs := user.NotificationPrefs
+	if !prefs.EmailEnabled(n.Event) {
+		return nil
+	}
+
+	frequency := prefs.DigestFrequency()
+	if frequency == models.DigestInstant {
+		return GetMailService().Enqueue(ctx, user.Email, mailer.TemplateNotification, map[string]any{
+			"Name":    user.FullName,
+			"Title":   n.Title,
+			"Message": n.Message,
+			"Link":    n.Link,
+		})
+	}
+
+	now := time.Now()
+	queued := models.QueuedNotification{
+		ID:        primitive.NewObjectID(),
+		UserID:    n.UserID,
+		Event:     n.Event,
+		Title:     n.Title,
+		Message:   n.Message,
+		Link:      n.Link,
+		CreatedAt: now,
+		DigestAt:  nextDigestAt(now, frequency),
+	}
+	_, err := database.DB.Collection(s.Collection).InsertOne(ctx, queued)
+	return err
+}
+
+// SendDigests emails every user with due queued notifications one digest and
+// returns how many digests were sent.
+func (s *NotificationService) SendDigests() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	collection := database.DB.Collection(s.Collection)
+	now := time.Now()
+
+	userIDs, err := collection.Distinct(ctx, "user_id", bson.M{"digest_at": bson.M{"$lte": now}})
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, raw := range userIDs {
+		userID, ok := raw.(primitive.ObjectID)
+		if !ok {
+			continue
+		}
+		if err := s.sendDigest(ctx, userID, now); err != nil {
+			log.WithError(err).Errorf("failed to send notification digest to user %s", userID.Hex())
+			continue
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+func (s *NotificationService) sendDigest(ctx context.Context, userID primitive.ObjectID, now time.Time) error {
+	collection := database.DB.Collection(s.Collection)
+
+	filter := bson.M{"user_id": userID, "digest_at": bson.M{"$lte": now}}
+	cursor, err := collection.Find(ctx, filter, options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}}))
+	if err != nil {
+		return err
+	}
+	var items []models.QueuedNotification
+	if err := cursor.All(ctx, &items); err != nil {
+		return err
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"full_name": 1, "email": 1})
+	err = database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user)
+	if err == mongo.ErrNoDocuments {
+		_, err = collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	// Deleting first keeps a second worker from mailing the same items.
+	res, err := collection.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return nil
+	}
+
+	return GetMailService().Enqueue(ctx, user.Email, mailer.TemplateDigest, map[string]any{
+		"Name":  user.FullName,
+		"Items": items,
+	})
+}
+
+// dueSoonDays reads NOTIFICATION_DUE_SOON_DAYS, how many days before the due
+// date the reminder is sent, defaulting to 1.
+func dueSoonDays() int {
+	return envInt("NOTIFICATION_DUE_SOON_DAYS", defaultDueSoonDays)
+}
+
+// doneStatusIDs returns the board columns named DONE, case-insensitively,
+// where issues count as finished. Issues closed with a resolution are
+// recognised by it instead.
+func doneStatusIDs(ctx context.Context) ([]primitive.ObjectID, error) {
+	filter := bson.M{"name": bson.M{"$regex": "^" + string(models.DONE) + "$", "$options": "i"}}
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := database.DB.Collection(GetStatusService().Collection).Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	ids := []primitive.ObjectID{}
+	for cursor.Next(ctx) {
+		var status models.Status
+		if err := cursor.Decode(&status); err != nil {
+			return nil, err
+		}
+		ids = append(ids, status.ID)
+	}
+	return ids, cursor.Err()
+}
+
+// NotifyDueSoon reminds assignees and watchers of open issues due within
+// dueSoonDays. Each due date is reminded about once; changing the due date
+// arms the reminder again.
+func (s *NotificationService) NotifyDueSoon() (int, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	doneIDs, err := doneStatusIDs(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+	now := time.Now().UTC()
+	today := now.Format("2006-01-02")
+	until := now.AddDate(0, 0, dueSoonDays()).Format("2006-01-02")
+
+	filter := bson.M{
+		"due_date":   bson.M{"$gte": today, "$lte": until},
+		"status_id":  bson.M{"$nin": doneIDs},
+		"resolution": bson.M{"$exists": false},
+		"$expr":      bson.M{"$ne": bson.A{"$due_reminder_for", "$due_date"}},
+		"$or": []bson.M{
+			{"assignee_id": bson.M{"$exists": true}},
+			{"watchers.0": bson.M{"$exists": true}},
+		},
+	}
+	cursor, err := issuesColl.Find(ctx, filter)
+	if err != nil {
+		return 0, err
+	}
+	var issues []models.Issue
+	if err := cursor.All(ctx, &issues); err != nil {
+		return 0, err
+	}
+
+	projects := map[primitive.ObjectID]*models.Project{}
+	reminded := 0
+	for _, issue := range issues {
+		project, ok := projects[issue.ProjectID]
+		if !ok {
+			project = activeProject(ctx, issue.ProjectID)
+			projects[issue.ProjectID] = project
+		}
+		if project == nil {
+			continue
+		}
+
+		res, err := issuesColl.UpdateOne(ctx,
+			bson.M{"_id": issue.ID, "due_reminder_for": bson.M{"$ne": issue.DueDate}},
+			bson.M{"$set": bson.M{"due_reminder_for": issue.DueDate}},
+		)
+		if err != nil {
+			return reminded, err
+		}
+		if res.ModifiedCount == 0 {
+			continue
+		}
+
+		s.notifyIssue(&issue, project, primitive.NilObjectID, models.EventIssueDueSoon,
+			fmt.Sprintf("'%s' is due on %s", issue.Title, issue.DueDate),
+			fmt.Sprintf("The issue '%s' in %s is due on %s.", issue.Title, project.Name, issue.DueDate),
+		)
+		reminded++
+	}
+	return reminded, nil
+}
+
+// StartNotificationWorker sends due digests and due-soon reminders every
+// interval.
+func StartNotificationWorker(interval time.Duration) {
+	s := GetNotificationService()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if sent, err := s.SendDigests(); err != nil {
+				log.WithError(err).Error("Notification digests failed")
+			} else if sent > 0 {
+				log.Infof("Sent %d notification digests", sent)
+			}
+			if reminded, err := s.NotifyDueSoon(); err != nil {
+				log.WithError(err).Error("Due soon reminders failed")
+			} else if reminded > 0 {
+				log.Infof("Sent due soon reminders for %d issues", reminded)
+			}
+		}
+	}()
+}
+
+// GetPreferences returns the notification preferences of a user.
+func (s *NotificationService) GetPreferences(userID primitive.ObjectID) (*models.NotificationPreferences, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"notification_prefs": 1})
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+
+	prefs := user.NotificationPrefs
+	prefs.Digest = prefs.DigestFrequency()
+	if prefs.EmailMuted == nil {
+		prefs.EmailMuted = []models.NotificationEvent{}
+	}
+	return &prefs, nil
+}
+
+// UpdatePreferences replaces the notification preferences of a user. Queued
+// notifications keep their digest time.
+func (s *NotificationService) UpdatePreferences(userID primitive.ObjectID, req request.NotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	prefs := models.NotificationPreferences{
+		EmailMuted: []models.NotificationEvent{},
+		Digest:     models.DigestFrequency(req.Digest),
+	}
+	if prefs.Digest == "" {
+		prefs.Digest = models.DigestInstant
+	}
+	if !prefs.Digest.IsValid() {
+		return nil, fmt.Errorf("invalid digest: %s", req.Digest)
+	}
+	for _, e := range req.EmailMuted {
+		event := models.NotificationEvent(e)
+		if !event.IsValid() {
+			return nil, fmt.Errorf("invalid notification event: %s", e)
+		}
+		if !slices.Contains(prefs.EmailMuted, event) {
+			prefs.EmailMuted = append(prefs.EmailMuted, event)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := database.DB.Collection(GetUserService().Collection).UpdateOne(ctx,
+		bson.M{"_id": userID},
+		bson.M{"$set": bson.M{"notification_prefs": prefs}},
+	)
+	if err != nil {
+		log.WithError(err).Error("failed to update notification preferences")
+		return nil, err
+	}
+	if res.MatchedCount == 0 {
+		return nil, fmt.Errorf("user not found")
+	}
+	return &prefs, nil
+}
+
+// issueRecipients returns the assignee and the watchers of an issue who are
+// still members of its project.
+func issueRecipients(issue *models.Issue, project *models.Project) []primitive.ObjectID {
+	candidates := slices.Clone(issue.WatcherIDs)
+	if !issue.AssigneeID.IsZero() && !slices.Contains(candidates, issue.AssigneeID) {
+		candidates = append(candidates, issue.AssigneeID)
+	}
+
+	recipients := make([]primitive.ObjectID, 0, len(candidates))
+	for _, userID := range candidates {
+		if userID == project.OwnerID || slices.Contains(project.TeamIDs, userID) {
+			recipients = append(recipients, userID)
+		}
+	}
+	return recipients
+}
+
+// notifyIssue notifies the assignee and the watchers of an issue.
+func (s *NotificationService) notifyIssue(issue *models.Issue, project *models.Project, actorID primitive.ObjectID, event models.NotificationEvent, title, message string) {
+	recipients := issueRecipients(issue, project)
+
+	notifications := make([]Notification, 0, len(recipients))
+	for _, userID := range recipients {
+		notifications = append(notifications, Notification{
+			UserID:    userID,
+			ActorID:   actorID,
+			ProjectID: issue.ProjectID,
+			Event:     event,
+			Title:     title,
+			Message:   message,
+			Link:      projectLink(issue.ProjectID),
+		})
+	}
+	s.Notify(notifications...)
+}
+
+// notifyAssigned tells the assignee of an issue that it was assigned to them.
+func (s *NotificationService) notifyAssigned(ctx context.Context, issue *models.Issue, actorID primitive.ObjectID) {
+	project := activeProjectName(ctx, issue.ProjectID)
+	s.Notify(Notification{
+		UserID:    issue.AssigneeID,
+		ActorID:   actorID,
+		ProjectID: issue.ProjectID,
+		Event:     models.EventIssueAssigned,
+		Title:     fmt.Sprintf("'%s' was assigned to you", issue.Title),
+		Message:   fmt.Sprintf("%s assigned you the issue '%s' in %s.", displayName(ctx, actorID), issue.Title, project),
+		Link:      projectLink(issue.ProjectID),
+	})
+}
+
+// notifyStatusChanged tells the watchers and the assignee of an issue that
+// its status changed.
+func (s *NotificationService) notifyStatusChanged(ctx context.Context, issue *models.Issue, actorID primitive.ObjectID, from, to string) {
+	project := activeProject(ctx, issue.ProjectID)
+	if project == nil {
+		return
+	}
+	s.notifyIssue(issue, project, actorID, models.EventIssueStatusChanged,
+		fmt.Sprintf("'%s' moved to %s", issue.Title, to),
+		fmt.Sprintf("%s moved the issue '%s' in %s from %s to %s.", displayName(ctx, actorID), issue.Title, project.Name, from, to),
+	)
+}
+
+// notifyIssueCreated tells the project team about a new issue.
+func (s *NotificationService) notifyIssueCreated(ctx context.Context, issue *models.Issue, actorID primitive.ObjectID) {
+	var project models.Project
+	opts := options.FindOne().SetProjection(bson.M{"name": 1, "owner_id": 1, "team": 1})
+	if err := database.DB.Collection(GetProjectService().Collection).FindOne(ctx, bson.M{"_id": issue.ProjectID}, opts).Decode(&project); err != nil {
+		log.WithError(err).Error("failed to load project team for notifications")
+		return
+	}
+
+	members := slices.Clone(project.TeamIDs)
+	if !project.OwnerID.IsZero() && !slices.Contains(members, project.OwnerID) {
+		members = append(members, project.OwnerID)
+	}
+
+	actor := displayName(ctx, actorID)
+	notifications := make([]Notification, 0, len(members))
+	for _, userID := range members {
+		notifications = append(notifications, Notification{
+			UserID:    userID,
+			ActorID:   actorID,
+			ProjectID: issue.ProjectID,
+			Event:     models.EventIssueCreated,
+			Title:     fmt.Sprintf("New issue '%s'", issue.Title),
+			Message:   fmt.Sprintf("%s created the issue '%s' in %s.", actor, issue.Title, project.Name),
+			Link:      projectLink(issue.ProjectID),
+		})
+	}
+	s.Notify(notifications...)
+}
+
+// projectLink points at the project page of the frontend.
+func projectLink(projectID primitive.ObjectID) string {
+	return frontendURL() + "/projects/" + projectID.Hex()
+}
+
+// displayName returns the full name of a user for notification texts.
+func displayName(ctx context.Context, userID primitive.ObjectID) string {
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"full_name": 1})
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		return "Someone"
+	}
+	return user.FullName
+}
+
+// activeProjectName returns the name of a project, or "" when it is deleted
+// or archived.
+func activeProjectName(ctx context.Context, projectID primitive.ObjectID) string {
+	if project := activeProject(ctx, projectID); project != nil {
+		return project.Name
+	}
+	return ""
+}
+
+// activeProject returns the name, owner and team of a project, or nil when it
+// is deleted or archived.
+func activeProject(ctx context.Context, projectID primitive.ObjectID) *models.Project {
+	var project models.Project
+	filter := bson.M{"_id": projectID, "deleted_at": bson.M{"$exists": false}, "status": bson.M{"$ne": models.ProjectArchived}}
+	opts := options.FindOne().SetProjection(bson.M{"name": 1, "owner_id": 1, "team": 1})
+	if err := database.DB.Collection(GetProjectService().Collection).FindOne(ctx, filter, opts).Decode(&project); err != nil {
+		return nil
+	}
+	return &project
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"managify/models"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestIssueRecipientsAreProjectMembers(t *testing.T) {
+	owner := primitive.NewObjectID()
+	member := primitive.NewObjectID()
+	former := primitive.NewObjectID()
+	project := &models.Project{OwnerID: owner, TeamIDs: []primitive.ObjectID{member}}
+
+	issue := &models.Issue{AssigneeID: former, WatcherIDs: []primitive.ObjectID{owner, member, former}}
+	got := issueRecipients(issue, project)
+	if !slices.Equal(got, []primitive.ObjectID{owner, member}) {
+		t.Errorf("recipients = %v, want the owner and the member", got)
+	}
+
+	issue = &models.Issue{AssigneeID: member}
+	if got := issueRecipients(issue, project); !slices.Equal(got, []primitive.ObjectID{member}) {
+		t.Errorf("recipients = %v, want the assignee", got)
+	}
+}
+
+func TestNotifyDueSoonSkipsDoneColumns(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		done := models.Status{ID: primitive.NewObjectID(), Name: "Done"}
+		project := models.Project{ID: primitive.NewObjectID(), Name: "Apollo", OwnerID: primitive.NewObjectID()}
+		issue := models.Issue{
+			ID:         primitive.NewObjectID(),
+			ProjectID:  project.ID,
+			DueDate:    "2026-10-18",
+			WatcherIDs: []primitive.ObjectID{primitive.NewObjectID()},
+		}
+
+		mt.AddMockResponses(
+			docsReply(t, done),
+			docsReply(t, issue),
+			docsReply(t, project),
+			updateReply(1),
+		)
+
+		reminded, err := GetNotificationService().NotifyDueSoon()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if reminded != 1 {
+			t.Errorf("reminded = %d, want 1", reminded)
+		}
+
+		mt.GetStartedEvent() // done columns
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		if _, err := filter.LookupErr("status"); err == nil {
+			t.Errorf("filter %s still uses the legacy status", filter)
+		}
+		excluded, _ := filter.Lookup("status_id", "$nin").Array().Values()
+		if len(excluded) != 1 || excluded[0].ObjectID() != done.ID {
+			t.Errorf("excluded columns = %v, want [%s]", excluded, done.ID)
+		}
+	})
+}
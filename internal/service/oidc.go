@@ -0,0 +1,306 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/response"
+	"managify/internal/oidc"
+	"managify/models"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// oidcLoginTTL is how long the user has to log in at the identity provider.
+const oidcLoginTTL = 10 * time.Minute
+
+// OIDCService implements single sign-on through an OpenID Connect identity
+// provider configured with the OIDC_* environment variables.
+type OIDCService struct {
+	Collection string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+var oidcService *OIDCService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetOIDCService() *OIDCService {
+	if oidcService == nil {
+		oidcService = &OIDCService{Collection: "oidc_logins"}
+	}
+	return oidcService
+}
+
+// oidcConfig reads OIDC_ISSUER, OIDC_CLIENT_ID, OIDC_CLIENT_SECRET,
+// OIDC_REDIRECT_URL and OIDC_SCOPES. The redirect URL defaults to the
+// /sso/callback page of the frontend.
+func oidcConfig() oidc.Config {
+	cfg := oidc.Config{
+		Issuer:       os.Getenv("OIDC_ISSUER"),
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  os.Getenv("OIDC_REDIRECT_URL"),
+		Scopes:       strings.Fields(os.Getenv("OIDC_SCOPES")),
+	}
+	if cfg.RedirectURL == "" {
+		cfg.RedirectURL = frontendURL() + "/sso/callback"
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+	return cfg
+}
+
+// Enabled reports whether single sign-on is configured.
+func (s *OIDCService) Enabled() bool {
+	cfg := oidcConfig()
+	return cfg.Issuer != "" && cfg.ClientID != ""
+}
+
+// getProvider discovers the identity provider on first use. A failed
+// discovery is retried on the next login.
+func (s *OIDCService) getProvider(ctx context.Context) (*oidc.Provider, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.provider != nil {
+		return s.provider, nil
+	}
+	if !s.Enabled() {
+		return nil, fmt.Errorf("single sign-on is not configured")
+	}
+	provider, err := oidc.NewProvider(ctx, oidcConfig())
+	if err != nil {
+		log.WithError(err).Error("failed to discover identity provider")
+		return nil, fmt.Errorf("identity provider is unavailable")
+	}
+	s.provider = provider
+	return provider, nil
+}
+
+// StartLogin returns the authorization URL the browser has to visit. The
+// state, nonce and PKCE verifier are kept until the provider redirects back.
+func (s *OIDCService) StartLogin() (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var values [3]string
+	for i := range values {
+		if values[i], err = oidc.RandomString(); err != nil {
+			return "", err
+		}
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	now := time.Now()
+	login := models.OIDCLogin{
+		ID:           primitive.NewObjectID(),
+		StateHash:    hashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oidcLoginTTL),
+	}
+	if _, err := database.DB.Collection(s.Collection).InsertOne(ctx, login); err != nil {
+		log.WithError(err).Error("failed to insert oidc login")
+		return "", err
+	}
+
+	return provider.AuthCodeURL(state, nonce, verifier), nil
+}
+
+// CompleteLogin redeems the code the identity provider redirected back with
+// and logs the matching user in, creating or linking the account on first
+// use. Local two-factor authentication is not asked for; the identity
+// provider decides how its users authenticate.
+func (s *OIDCService) CompleteLogin(code, state string, info SessionInfo) (*response.UserLoginResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	provider, err := s.getProvider(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var login models.OIDCLogin
+	filter := bson.M{"state_hash": hashToken(state), "expires_at": bson.M{"$gt": time.Now()}}
+	if err := database.DB.Collection(s.Collection).FindOneAndDelete(ctx, filter).Decode(&login); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("single sign-on request is invalid or has expired, try again")
+		}
+		return nil, err
+	}
+
+	claims, err := provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		log.WithError(err).Warn("oidc code exchange failed")
+		return nil, fmt.Errorf("single sign-on failed")
+	}
+
+	user, err := s.findOrCreateUser(ctx, provider.Issuer(), claims)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("User logged in through single sign-on: %s", user.Email)
+	return GetUserService().issueLogin(user, info)
+}
+
+// findOrCreateUser returns the account linked to the identity provider
+// account. Otherwise an existing account is linked when the provider has
+// verified its email and the account has no two-factor authentication, or a
+// new account is created.
+func (s *OIDCService) findOrCreateUser(ctx context.Context, issuer string, claims *oidc.Claims) (*models.User, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+
+	var user models.User
+	err := usersColl.FindOne(ctx, bson.M{"oidc_issuer": issuer, "oidc_subject": claims.Subject}).Decode(&user)
+	if err == nil {
+		return &user, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	if claims.Email == "" {
+		return nil, fmt.Errorf("identity provider did not share an email address")
+	}
+
+	err = usersColl.FindOne(ctx, bson.M{"email": claims.Email}).Decode(&user)
+	if err == nil {
+		if !claims.EmailVerified {
+			return nil, fmt.Errorf("an account with this email exists, log in with your password")
+		}
+		if user.OIDCSubject != "" {
+			return nil, fmt.Errorf("this account is linked to another single sign-on identity")
+		}
+		// Single sign-on skips the local second step, so linking would
+		// bypass the authenticator the user enrolled.
+		if user.TwoFactorEnabled {
+			return nil, fmt.Errorf("this account uses two-factor authentication, log in with your password and authentication code")
+		}
+		update := bson.M{"$set": bson.M{"oidc_issuer": issuer, "oidc_subject": claims.Subject, "isverified": true}}
+		filter := bson.M{"_id": user.ID, "oidc_subject": bson.M{"$exists": false}, "two_factor_enabled": bson.M{"$ne": true}}
+		res, err := usersColl.UpdateOne(ctx, filter, update)
+		if err != nil {
+			return nil, err
+		}
+		if res.ModifiedCount == 0 {
+			return nil, fmt.Errorf("this account is linked to another single sign-on identity")
+		}
+		user.OIDCIssuer, user.OIDCSubject, user.IsVerified = issuer, claims.Subject, true
+		log.Infof("Linked user %s to single sign-on", user.ID.Hex())
+		return &user, nil
+	}
+	if err != mongo.ErrNoDocuments {
+		return nil, err
+	}
+
+	return s.createUser(ctx, issuer, claims)
+}
+
+// createUser provisions an account for a first single sign-on login. It gets
+// an unusable random password, which the user can replace through the
+// password reset flow.
+func (s *OIDCService) createUser(ctx context.Context, issuer string, claims *oidc.Claims) (*models.User, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+
+	name, err := s.availableName(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	password, err := generateToken(32)
+	if err != nil {
+		return nil, err
+	}
+	hashed, err := GetUserService().EncryptPassword([]byte(password))
+	if err != nil {
+		return nil, err
+	}
+
+	user := models.User{
+		ID:          primitive.NewObjectID(),
+		FullName:    name,
+		Email:       claims.Email,
+		Password:    string(hashed),
+		IsVerified:  claims.EmailVerified,
+		OIDCIssuer:  issuer,
+		OIDCSubject: claims.Subject,
+	}
+	var verifyToken string
+	if !user.IsVerified {
+		if verifyToken, err = newVerification(&user); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := usersColl.InsertOne(ctx, user); err != nil {
+		log.WithError(err).Error("failed to insert single sign-on user")
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := GetSubscriptionService().CreateSubscription(&models.Subscription{
+		SubscriptionStartDate: now,
+		SubscriptionEndDate:   now,
+		PlanType:              models.PlanBasic,
+		IsValid:               true,
+		UserID:                user.ID,
+	}); err != nil {
+		log.WithError(err).Error("failed to create subscription for single sign-on user")
+		return nil, err
+	}
+
+	if verifyToken != "" {
+		if err := sendVerificationEmail(ctx, &user, verifyToken); err != nil {
+			log.WithError(err).Errorf("failed to queue verification email to %s", user.Email)
+		}
+	}
+
+	log.Infof("Created user %s through single sign-on", user.ID.Hex())
+	return &user, nil
+}
+
+// availableName picks a unique full name from the name claim, falling back
+// to the local part of the email and numbering duplicates.
+func (s *OIDCService) availableName(ctx context.Context, claims *oidc.Claims) (string, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+
+	base := strings.TrimSpace(claims.Name)
+	if base == "" {
+		base, _, _ = strings.Cut(claims.Email, "@")
+	}
+	name := base
+	for i := 2; i <= 100; i++ {
+		count, err := usersColl.CountDocuments(ctx, bson.M{"full_name": name})
+		if err != nil {
+			return "", err
+		}
+		if count == 0 {
+			return name, nil
+		}
+		name = fmt.Sprintf("%s %d", base, i)
+	}
+	return "", fmt.Errorf("could not find a free name for %s", base)
+}
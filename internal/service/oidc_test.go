@@ -0,0 +1,61 @@
+package service
+
+import (
+	"context"
+	"managify/internal/oidc"
+	"managify/models"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testIssuer = "https://idp.example.com"
+
+func TestFindOrCreateUserLinksVerifiedEmail(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID(), Email: "jane@example.com"}
+	claims := &oidc.Claims{Subject: "sub-1", Email: user.Email, EmailVerified: true}
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(
+			docsReply(t), // no account linked to the subject yet
+			docsReply(t, user),
+			updateReply(1),
+		)
+
+		linked, err := GetOIDCService().findOrCreateUser(context.Background(), testIssuer, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if linked.ID != user.ID || linked.OIDCSubject != "sub-1" || linked.OIDCIssuer != testIssuer {
+			t.Errorf("linked user = %+v", linked)
+		}
+
+		mt.GetStartedEvent()
+		mt.GetStartedEvent()
+		q, _ := sentUpdate(t, mt)
+		filter := q.String()
+		if !strings.Contains(filter, `"two_factor_enabled": {"$ne": true}`) {
+			t.Errorf("link filter %s does not exclude two-factor accounts", filter)
+		}
+	})
+}
+
+func TestFindOrCreateUserRefusesTwoFactorAccounts(t *testing.T) {
+	user := models.User{ID: primitive.NewObjectID(), Email: "jane@example.com", TwoFactorEnabled: true}
+	claims := &oidc.Claims{Subject: "sub-1", Email: user.Email, EmailVerified: true}
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(docsReply(t), docsReply(t, user))
+
+		_, err := GetOIDCService().findOrCreateUser(context.Background(), testIssuer, claims)
+		if err == nil || !strings.Contains(err.Error(), "two-factor") {
+			t.Fatalf("findOrCreateUser = %v, want two-factor error", err)
+		}
+		if slices.Contains(sentCommands(mt), "update") {
+			t.Error("two-factor account was linked")
+		}
+	})
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"managify/database"
+	"managify/internal/mailer"
+	"managify/models"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	defaultPasswordResetMinutes  = 30
+	defaultPasswordResetCooldown = 60
+)
+
+var errResetTokenInvalid = errors.New("reset link is invalid or has expired")
+
+type PasswordResetService struct {
+	Collection string
+}
+
+var passwordResetService *PasswordResetService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetPasswordResetService() *PasswordResetService {
+	if passwordResetService == nil {
+		passwordResetService = &PasswordResetService{Collection: "password_resets"}
+	}
+	return passwordResetService
+}
+
+// passwordResetTTL reads PASSWORD_RESET_TTL_MINUTES, defaulting to 30 minutes.
+func passwordResetTTL() time.Duration {
+	minutes, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_TTL_MINUTES"))
+	if err != nil || minutes <= 0 {
+		minutes = defaultPasswordResetMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// passwordResetCooldown reads PASSWORD_RESET_RESEND_SECONDS, the minimum time
+// between two reset emails to the same account, defaulting to 60 seconds.
+func passwordResetCooldown() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_RESEND_SECONDS"))
+	if err != nil || seconds < 0 {
+		seconds = defaultPasswordResetCooldown
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// IsInvalidResetToken reports whether err was returned because the reset
+// token is unknown, used or expired.
+func IsInvalidResetToken(err error) bool {
+	return errors.Is(err, errResetTokenInvalid)
+}
+
+// frontendURL is the public base URL of links sent to users. It reads
+// PUBLIC_BASE_URL, then FRONTEND_URL, defaulting to the local dev server.
+func frontendURL() string {
+	for _, name := range []string{"PUBLIC_BASE_URL", "FRONTEND_URL"} {
+		if url := os.Getenv(name); url != "" {
+			return strings.TrimRight(url, "/")
+		}
+	}
+	return "http://localhost:5173"
+}
+
+// RequestReset emails a reset link to the account with the given address.
+// Unknown addresses and requests within the resend cooldown are ignored so the
+// endpoint does not reveal which emails are registered. Any earlier unused
+// token of the user stops working.
+func (s *PasswordResetService) RequestReset(email string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	if err := usersColl.FindOne(ctx, bson.M{"email": strings.TrimSpace(email)}).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			log.Info("Password reset requested for an unknown email")
+			return nil
+		}
+		log.WithError(err).Error("failed to look up user for password reset")
+		return err
+	}
+
+	// The sent_at condition makes concurrent requests race for a single slot.
+	now := time.Now()
+	filter := bson.M{"_id": user.ID}
+	if previous := user.PasswordResetSentAt; previous != nil {
+		if previous.Add(passwordResetCooldown()).After(now) {
+			log.Infof("Password reset for user %s requested within the cooldown", user.ID.Hex())
+			return nil
+		}
+		filter["password_reset_sent_at"] = *previous
+	} else {
+		filter["password_reset_sent_at"] = bson.M{"$exists": false}
+	}
+	res, err := usersColl.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"password_reset_sent_at": now}})
+	if err != nil {
+		log.WithError(err).Error("failed to reserve password reset slot")
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		log.Infof("Password reset for user %s requested within the cooldown", user.ID.Hex())
+		return nil
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	if _, err := collection.DeleteMany(ctx, bson.M{"user_id": user.ID, "used_at": bson.M{"$exists": false}}); err != nil {
+		log.WithError(err).Error("failed to revoke earlier password resets")
+		return err
+	}
+
+	token, err := generateToken(32)
+	if err != nil {
+		return err
+	}
+
+	ttl := passwordResetTTL()
+	reset := models.PasswordReset{
+		ID:        primitive.NewObjectID(),
+		UserID:    user.ID,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	}
+	if _, err := collection.InsertOne(ctx, reset); err != nil {
+		log.WithError(err).Error("failed to insert password reset")
+		return err
+	}
+
+	if err := sendPasswordResetEmail(ctx, &user, token, ttl); err != nil {
+		log.WithError(err).Errorf("failed to queue password reset email for user %s", user.ID.Hex())
+		return err
+	}
+
+	return nil
+}
+
+// ConfirmReset sets a new password using a reset token and logs the user out
+// of every session. The token is only used up together with the password
+// change, so a failed update leaves the link working.
+func (s *PasswordResetService) ConfirmReset(token, password string) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	us := GetUserService()
+	hashedPassword, err := us.EncryptPassword([]byte(password))
+	if err != nil {
+		log.Errorf("Password encryption failed: %v", err)
+		return err
+	}
+
+	var reset models.PasswordReset
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		now := time.Now()
+		filter := bson.M{
+			"token_hash": hashToken(token),
+			"used_at":    bson.M{"$exists": false},
+			"expires_at": bson.M{"$gt": now},
+		}
+		if err := collection.FindOneAndUpdate(ctx, filter, bson.M{"$set": bson.M{"used_at": now}}).Decode(&reset); err != nil {
+			if err == mongo.ErrNoDocuments {
+				return errResetTokenInvalid
+			}
+			return err
+		}
+
+		usersColl := database.DB.Collection(us.Collection)
+		if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": reset.UserID}, bson.M{"$set": bson.M{"password": string(hashedPassword)}}); err != nil {
+			return fmt.Errorf("failed to update password: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		if !IsInvalidResetToken(err) {
+			log.WithError(err).Error("failed to reset password")
+		}
+		return err
+	}
+
+	if err := GetAuthService().LogoutAll(reset.UserID); err != nil {
+		return err
+	}
+
+	log.Infof("Password reset for user %s", reset.UserID.Hex())
+	return nil
+}
+
+// sendPasswordResetEmail queues the reset link for the user.
+func sendPasswordResetEmail(ctx context.Context, user *models.User, token string, ttl time.Duration) error {
+	return GetMailService().Enqueue(ctx, user.Email, mailer.TemplatePasswordReset, map[string]any{
+		"Name":    user.FullName,
+		"Link":    frontendURL() + "/reset-password?token=" + token,
+		"Minutes": int(ttl.Minutes()),
+	})
+}
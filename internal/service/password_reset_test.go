@@ -0,0 +1,82 @@
+package service
+
+import (
+	"managify/models"
+	"slices"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestRequestResetWithinCooldown(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		sentAt := time.Now().Add(-10 * time.Second).Truncate(time.Millisecond)
+		mt.AddMockResponses(docsReply(t, models.User{ID: primitive.NewObjectID(), Email: "jane@example.com", PasswordResetSentAt: &sentAt}))
+
+		if err := GetPasswordResetService().RequestReset("jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		if got := sentCommands(mt); !slices.Equal(got, []string{"find"}) {
+			t.Errorf("commands = %v, want only the user lookup", got)
+		}
+	})
+}
+
+func TestRequestResetLosesConcurrentSlot(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		sentAt := time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		mt.AddMockResponses(
+			docsReply(t, models.User{ID: primitive.NewObjectID(), Email: "jane@example.com", PasswordResetSentAt: &sentAt}),
+			updateReply(0),
+		)
+
+		if err := GetPasswordResetService().RequestReset("jane@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		mt.GetStartedEvent()
+		q, _ := sentUpdate(t, mt)
+		if got := q.Lookup("password_reset_sent_at").Time(); !got.Equal(sentAt) {
+			t.Errorf("slot filter = %v, want %v", got, sentAt)
+		}
+		if slices.Contains(sentCommands(mt), "insert") {
+			t.Error("a reset was issued although another request took the slot")
+		}
+	})
+}
+
+func TestConfirmResetInvalidToken(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+
+		err := GetPasswordResetService().ConfirmReset("unknown", "Str0ng!Passw0rd")
+		if !IsInvalidResetToken(err) {
+			t.Fatalf("err = %v, want an invalid token error", err)
+		}
+	})
+}
+
+func TestConfirmResetKeepsSessionsOnFailedUpdate(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		reset := models.PasswordReset{ID: primitive.NewObjectID(), UserID: primitive.NewObjectID()}
+		mt.AddMockResponses(
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: docsValue(t, reset)}),
+			mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11600, Message: "interrupted"}),
+		)
+
+		err := GetPasswordResetService().ConfirmReset("token", "Str0ng!Passw0rd")
+		if err == nil || IsInvalidResetToken(err) {
+			t.Fatalf("err = %v, want the update error", err)
+		}
+		if !slices.Contains(sentCommands(mt), "abortTransaction") {
+			t.Errorf("commands = %v, the used token was not rolled back", sentCommands(mt))
+		}
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "update" && e.Command.Lookup("updates", "0", "u", "$set", "tokens_valid_after").Type != 0 {
+				t.Error("sessions were logged out although the password was not changed")
+			}
+		}
+	})
+}
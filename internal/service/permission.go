@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	errNotProjectMember = errors.New("user is not in project")
+	errForbidden        = errors.New("forbidden")
+)
+
+// rolePermissions is the permission matrix of the built-in project roles.
+// Team members without an explicit role are treated as MEMBER.
+var rolePermissions = map[models.ProjectRole][]models.Permission{
+	models.RoleOwner: {
+		models.PermProjectView, models.PermProjectUpdate, models.PermProjectDelete,
+		models.PermIssueCreate, models.PermIssueUpdate, models.PermIssueDelete, models.PermIssueAssign,
+		models.PermCommentCreate, models.PermCommentModerate,
+		models.PermStatusManage, models.PermRoleManage, models.PermInviteSend, models.PermMemberRemove,
+		models.PermWebhookManage,
+	},
+	models.RoleMaintainer: {
+		models.PermProjectView, models.PermProjectUpdate,
+		models.PermIssueCreate, models.PermIssueUpdate, models.PermIssueDelete, models.PermIssueAssign,
+		models.PermCommentCreate, models.PermCommentModerate,
+		models.PermStatusManage, models.PermInviteSend, models.PermMemberRemove,
+		models.PermWebhookManage,
+	},
+	models.RoleMember: {
+		models.PermProjectView,
+		models.PermIssueCreate, models.PermIssueUpdate, models.PermIssueAssign,
+		models.PermCommentCreate,
+	},
+	models.RoleViewer: {
+		models.PermProjectView,
+	},
+}
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+// IsAssignableRole reports whether a role can be given to a team member.
+// OWNER is implied by Project.OwnerID and cannot be assigned.
+func IsAssignableRole(role models.ProjectRole) bool {
+	_, ok := rolePermissions[role]
+	return ok && role != models.RoleOwner
+}
+
+// ProjectAccess is the role a user holds in a project and the permissions it
+// grants.
+type ProjectAccess struct {
+	Role        models.ProjectRole
+	Permissions []models.Permission
+}
+
+func (a *ProjectAccess) Allows(perm models.Permission) bool {
+	return slices.Contains(a.Permissions, perm)
+}
+
+// GetUserAccess resolves the role and permissions of a user in a live
+// project. Custom roles take their permissions from the project's
+// RoleDefinition; a role whose definition no longer exists falls back to MEMBER.
+func (s *RoleService) GetUserAccess(userID, projectID primitive.ObjectID) (*ProjectAccess, error) {
+	return s.getUserAccess(userID, projectID, false)
+}
+
+// GetUserAccessIncludingDeleted is GetUserAccess for projects that may be in
+// the trash, used to authorize restoring and permanently deleting them.
+func (s *RoleService) GetUserAccessIncludingDeleted(userID, projectID primitive.ObjectID) (*ProjectAccess, error) {
+	return s.getUserAccess(userID, projectID, true)
+}
+
+func (s *RoleService) getUserAccess(userID, projectID primitive.ObjectID, includeDeleted bool) (*ProjectAccess, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{"_id": projectID}
+	if !includeDeleted {
+		filter["deleted_at"] = bson.M{"$exists": false}
+	}
+	projectsColl := database.DB.Collection(GetProjectService().Collection)
+	var project models.Project
+	err := projectsColl.FindOne(ctx, filter).Decode(&project)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+
+	if project.OwnerID == userID {
+		return builtinAccess(models.RoleOwner), nil
+	}
+	if !slices.Contains(project.TeamIDs, userID) {
+		return nil, errNotProjectMember
+	}
+
+	var role models.Role
+	err = database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"user_id": userID, "project_id": projectID}).Decode(&role)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return builtinAccess(models.RoleMember), nil
+		}
+		return nil, err
+	}
+
+	if !role.DefinitionID.IsZero() {
+		definition, err := GetRoleDefinitionService().getDefinition(ctx, projectID, role.DefinitionID)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return builtinAccess(models.RoleMember), nil
+			}
+			return nil, err
+		}
+		return &ProjectAccess{
+			Role:        models.ProjectRole(definition.Name),
+			Permissions: definition.Permissions,
+		}, nil
+	}
+
+	if _, ok := rolePermissions[models.ProjectRole(role.RoleName)]; !ok {
+		return builtinAccess(models.RoleMember), nil
+	}
+	return builtinAccess(models.ProjectRole(role.RoleName)), nil
+}
+
+// GetUserRole resolves the name of the role a user holds in a live project.
+func (s *RoleService) GetUserRole(userID, projectID primitive.ObjectID) (models.ProjectRole, error) {
+	access, err := s.GetUserAccess(userID, projectID)
+	if err != nil {
+		return "", err
+	}
+	return access.Role, nil
+}
+
+func builtinAccess(role models.ProjectRole) *ProjectAccess {
+	return &ProjectAccess{Role: role, Permissions: rolePermissions[role]}
+}
+
+// IsBuiltinRole reports whether name is one of the fixed project roles,
+// ignoring case.
+func IsBuiltinRole(name string) bool {
+	_, ok := rolePermissions[models.ProjectRole(strings.ToUpper(strings.TrimSpace(name)))]
+	return ok
+}
+
+func (s *RoleService) HasPermission(userID, projectID primitive.ObjectID, perm models.Permission) (bool, error) {
+	access, err := s.GetUserAccess(userID, projectID)
+	if err != nil {
+		return false, err
+	}
+	return access.Allows(perm), nil
+}
+
+// Authorize returns an error unless the user holds perm in the project.
+func (s *RoleService) Authorize(userID, projectID primitive.ObjectID, perm models.Permission) error {
+	ok, err := s.HasPermission(userID, projectID, perm)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("%w: missing permission %s", errForbidden, perm)
+	}
+	return nil
+}
+
+// IsForbidden reports whether err was returned because of a missing permission.
+func IsForbidden(err error) bool {
+	return errors.Is(err, errForbidden)
+}
+
+// IsNotProjectMember reports whether err was returned because the user is not
+// part of the project.
+func IsNotProjectMember(err error) bool {
+	return errors.Is(err, errNotProjectMember)
+}
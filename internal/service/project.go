@@ -2,11 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"managify/database"
 
 	"managify/models"
+	"slices"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -16,6 +18,8 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var errMemberNotFound = errors.New("member not found in project")
+
 type ProjectService struct {
 	Collection string
 }
@@ -43,45 +47,56 @@ func (s *ProjectService) CreateProject(project *models.Project, user *models.Use
 
 	userColl := database.DB.Collection("users")
 	projectColl := database.DB.Collection(s.Collection)
-	subColl := database.DB.Collection("subscriptions")
 
-	var subscription models.Subscription
-	if err := subColl.FindOne(ctx, bson.M{"user_id": user.ID, "is_valid": true}).Decode(&subscription); err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("no active subscription found")
+	var template *models.ProjectTemplate
+	if project.Template != "" {
+		t, err := GetProjectTemplateService().GetTemplate(ctx, project.Template)
+		if err != nil {
+			return nil, err
 		}
-		return nil, fmt.Errorf("failed to check subscription: %w", err)
-	}
-
-	filter := bson.M{"_id": user.ID}
-	if subscription.PlanType == models.PlanBasic {
-		filter["project_size"] = bson.M{"$lt": 3}
+		template = t
 	}
 
-	update := bson.M{"$inc": bson.M{"project_size": 1}}
-	res, err := userColl.UpdateOne(ctx, filter, update)
-	if err != nil {
-		return nil, fmt.Errorf("failed to update project size: %w", err)
-	}
-
-	if res.ModifiedCount == 0 {
-		return nil, fmt.Errorf("plan limit reached: BASIC users can only create up to 3 projects")
+	if err := reserveProjectSlot(ctx, user.ID); err != nil {
+		return nil, err
 	}
 
 	project.ID = primitive.NewObjectID()
 	project.OwnerID = user.ID
+	project.Status = models.ProjectActive
+	if template != nil {
+		for _, tag := range template.Tags {
+			if !slices.Contains(project.Tags, tag) {
+				project.Tags = append(project.Tags, tag)
+			}
+		}
+	}
 
-	if _, err := projectColl.InsertOne(ctx, project); err != nil {
-
-		_, err = userColl.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$inc": bson.M{"project_size": -1}})
-		return nil, fmt.Errorf("failed to insert project: %w", err)
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := projectColl.InsertOne(ctx, project); err != nil {
+			return fmt.Errorf("failed to insert project: %w", err)
+		}
+		if template != nil {
+			return applyTemplate(ctx, template, project, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		if _, uerr := userColl.UpdateOne(ctx, bson.M{"_id": user.ID}, bson.M{"$inc": bson.M{"project_size": -1}}); uerr != nil {
+			log.WithError(uerr).Error("failed to release project slot")
+		}
+		return nil, err
 	}
 
+	message := "Project has been created"
+	if template != nil {
+		message += " from template " + template.Name
+	}
 	projectLog := models.ProjectLog{
 		ID:        primitive.NewObjectID(),
 		ProjectID: project.ID.Hex(),
 		UserID:    user.ID.Hex(),
-		Message:   "Project has been created",
+		Message:   message,
 		Timestamp: time.Now(),
 	}
 	if err := GetLogService().CreateLog(&projectLog); err != nil {
@@ -91,6 +106,37 @@ func (s *ProjectService) CreateProject(project *models.Project, user *models.Use
 	return project, nil
 }
 
+// reserveProjectSlot increments the owner's project count, enforcing the
+// project limit of the BASIC plan.
+func reserveProjectSlot(ctx context.Context, ownerID primitive.ObjectID) error {
+	userColl := database.DB.Collection("users")
+	subColl := database.DB.Collection("subscriptions")
+
+	var subscription models.Subscription
+	if err := subColl.FindOne(ctx, bson.M{"user_id": ownerID, "is_valid": true}).Decode(&subscription); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("no active subscription found")
+		}
+		return fmt.Errorf("failed to check subscription: %w", err)
+	}
+
+	filter := bson.M{"_id": ownerID}
+	if subscription.PlanType == models.PlanBasic {
+		filter["project_size"] = bson.M{"$lt": 3}
+	}
+
+	update := bson.M{"$inc": bson.M{"project_size": 1}}
+	res, err := userColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		return fmt.Errorf("failed to update project size: %w", err)
+	}
+
+	if res.ModifiedCount == 0 {
+		return fmt.Errorf("plan limit reached: BASIC users can only create up to 3 projects")
+	}
+	return nil
+}
+
 func reduceProjectSize(ownerID primitive.ObjectID) error {
 	log.Debugf("reduceProjectSize called for ownerID=%s", ownerID.Hex())
 
@@ -118,8 +164,11 @@ func reduceProjectSize(ownerID primitive.ObjectID) error {
 	return nil
 }
 
-func (s *ProjectService) DeleteProjectById(objID primitive.ObjectID, user *models.User) error {
-	log.Debugf("DeleteProjectById called with objID=%s, userID=%s", objID.Hex(), user.ID.Hex())
+// DeleteProjectById removes a project and everything that belongs to it. Unless
+// permanent is set or retention is disabled, the project is only marked as
+// deleted and can be restored until the purge job removes it.
+func (s *ProjectService) DeleteProjectById(objID primitive.ObjectID, user *models.User, permanent bool) error {
+	log.Debugf("DeleteProjectById called with objID=%s, userID=%s, permanent=%v", objID.Hex(), user.ID.Hex(), permanent)
 
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -141,15 +190,44 @@ func (s *ProjectService) DeleteProjectById(objID primitive.ObjectID, user *model
 		return fmt.Errorf("unauthorized: only owner or admin can delete")
 	}
 
-	res, err := collection.DeleteOne(ctx, bson.M{"_id": objID})
-	if err != nil {
-		log.WithError(err).Error("Failed to delete project")
+	retention := projectRetention()
+	if permanent || retention <= 0 {
+		if err := s.purgeProject(&project); err != nil {
+			return err
+		}
+		if project.DeletedAt == nil {
+			reduceProjectSize(project.OwnerID)
+		}
+		log.Infof("Project permanently deleted: %s", objID.Hex())
+		return nil
+	}
+
+	if project.DeletedAt != nil {
+		return fmt.Errorf("project is already deleted")
+	}
+
+	now := time.Now()
+	purgeAt := now.Add(retention)
+	update := bson.M{"$set": bson.M{"deleted_at": now, "purge_at": purgeAt}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": objID}, update); err != nil {
+		log.WithError(err).Error("Failed to soft delete project")
 		return err
 	}
 
 	reduceProjectSize(project.OwnerID)
 
-	log.Infof("Project deleted successfully: %s, deletedCount=%d", objID.Hex(), res.DeletedCount)
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: objID.Hex(),
+		UserID:    user.ID.Hex(),
+		Message:   "Project has been deleted, it can be restored until " + purgeAt.Format(time.RFC1123),
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return err
+	}
+
+	log.Infof("Project soft deleted: %s, purge at %s", objID.Hex(), purgeAt)
 	return nil
 }
 
@@ -161,7 +239,8 @@ func (s *ProjectService) GetProject(projectID primitive.ObjectID, user *models.U
 
 	var project models.Project
 	err := collection.FindOne(ctx, bson.M{
-		"_id": projectID,
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
 		"$or": []bson.M{
 			{"owner_id": user.ID},
 			{"team": bson.M{"$in": []primitive.ObjectID{user.ID}}},
@@ -188,7 +267,7 @@ func (s *ProjectService) IsProjectValid(projectID primitive.ObjectID) (bool, err
 	defer cancel()
 
 	var project models.Project
-	err := collection.FindOne(ctx, bson.M{"_id": projectID}).Decode(&project)
+	err := collection.FindOne(ctx, bson.M{"_id": projectID, "deleted_at": bson.M{"$exists": false}}).Decode(&project)
 	if err != nil {
 		log.WithError(err).Error("failed to fetch project")
 		return false, err
@@ -204,7 +283,8 @@ func (s *ProjectService) IsUserInProject(userID, projectID primitive.ObjectID) (
 	defer cancel()
 
 	filter := bson.M{
-		"_id": projectID,
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
 		"$or": []bson.M{
 			{"owner_id": userID},
 			{"team": userID},
@@ -230,12 +310,7 @@ func (s *ProjectService) GetProjectsByUserId(userIDHex string) ([]*models.Projec
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	filter := bson.M{
-		"$or": []bson.M{
-			{"owner_id": userObjID},
-			{"team": bson.M{"$in": []primitive.ObjectID{userObjID}}},
-		},
-	}
+	filter := memberProjectsFilter(userObjID)
 
 	opts := options.Find().
 		SetLimit(50)
@@ -258,6 +333,42 @@ func (s *ProjectService) GetProjectsByUserId(userIDHex string) ([]*models.Projec
 	return projects, nil
 }
 
+// memberProjectsFilter matches the live projects a user owns or belongs to.
+func memberProjectsFilter(userID primitive.ObjectID) bson.M {
+	return bson.M{
+		"deleted_at": bson.M{"$exists": false},
+		"$or": []bson.M{
+			{"owner_id": userID},
+			{"team": bson.M{"$in": []primitive.ObjectID{userID}}},
+		},
+	}
+}
+
+// GetMemberProjectIDs returns the IDs of every project the user owns or belongs to.
+func (s *ProjectService) GetMemberProjectIDs(userID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetProjection(bson.M{"_id": 1})
+	cursor, err := collection.Find(ctx, memberProjectsFilter(userID), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var ids []primitive.ObjectID
+	for cursor.Next(ctx) {
+		var project models.Project
+		if err := cursor.Decode(&project); err != nil {
+			return nil, err
+		}
+		ids = append(ids, project.ID)
+	}
+
+	return ids, cursor.Err()
+}
+
 func (s *ProjectService) GetProjectWithTeam(projectID primitive.ObjectID, user *models.User) (*models.Project, []models.User, error) {
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -265,7 +376,8 @@ func (s *ProjectService) GetProjectWithTeam(projectID primitive.ObjectID, user *
 
 	var project models.Project
 	err := collection.FindOne(ctx, bson.M{
-		"_id": projectID,
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
 		"$or": []bson.M{
 			{"owner_id": user.ID},
 			{"team": bson.M{"$in": []primitive.ObjectID{user.ID}}},
@@ -299,32 +411,97 @@ func (s *ProjectService) GetProjectWithTeam(projectID primitive.ObjectID, user *
 	return &project, teamMembers, nil
 }
 
-func (s *ProjectService) DeleteMemberFromProjectById(userId, memberId primitive.ObjectID) error {
+// DeleteMemberFromProjectById removes a member from the project team together
+// with their role and any references the user keeps to the project.
+func (s *ProjectService) DeleteMemberFromProjectById(projectId, userId, memberId primitive.ObjectID) error {
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	var project models.Project
-	filter := bson.M{"owner_id": userId}
-	err := collection.FindOne(ctx, filter).Decode(&project)
-	if err != nil {
-		log.WithError(err).Error("failed to fetch project")
+	if err := GetRoleService().Authorize(userId, projectId, models.PermMemberRemove); err != nil {
+		return err
+	}
+	if err := s.EnsureWritable(projectId); err != nil {
 		return err
 	}
 
-	res, err := collection.UpdateOne(
-		ctx,
-		bson.M{"owner_id": userId},
-		bson.M{"$pull": bson.M{"team": memberId}},
-	)
+	// Member removal also clears their assignments and watches on the
+	// project's issues so nothing keeps pointing at a non-member.
+	err := database.WithTransaction(ctx, func(ctx context.Context) error {
+		res, err := collection.UpdateOne(
+			ctx,
+			bson.M{"_id": projectId, "owner_id": bson.M{"$ne": memberId}},
+			bson.M{"$pull": bson.M{"team": memberId}},
+		)
+		if err != nil {
+			return fmt.Errorf("failed to remove member from project: %w", err)
+		}
+		if res.ModifiedCount == 0 {
+			return errMemberNotFound
+		}
 
+		if _, err := database.DB.Collection(GetRoleService().Collection).DeleteMany(ctx, bson.M{"project_id": projectId, "user_id": memberId}); err != nil {
+			return fmt.Errorf("failed to delete member roles: %w", err)
+		}
+
+		issuesColl := database.DB.Collection(GetIssueService().Collection)
+		assigned := bson.M{"project_id": projectId, "assignee_id": memberId}
+		cursor, err := issuesColl.Find(ctx, assigned, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		var issueIDs []primitive.ObjectID
+		for cursor.Next(ctx) {
+			var issue models.Issue
+			if err := cursor.Decode(&issue); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+			issueIDs = append(issueIDs, issue.ID)
+		}
+		if err := cursor.Err(); err != nil {
+			cursor.Close(ctx)
+			return err
+		}
+		cursor.Close(ctx)
+
+		if len(issueIDs) > 0 {
+			if _, err := issuesColl.UpdateMany(ctx, assigned, bson.M{"$unset": bson.M{"assignee_id": ""}}); err != nil {
+				return fmt.Errorf("failed to unassign member issues: %w", err)
+			}
+		}
+		if _, err := issuesColl.UpdateMany(ctx,
+			bson.M{"project_id": projectId, "watchers": memberId},
+			bson.M{"$pull": bson.M{"watchers": memberId}},
+		); err != nil {
+			return fmt.Errorf("failed to remove member from watchers: %w", err)
+		}
+
+		userUpdate := bson.M{"$pull": bson.M{"team_projects": projectId}}
+		if len(issueIDs) > 0 {
+			userUpdate = bson.M{"$pull": bson.M{
+				"team_projects":   projectId,
+				"assigned_issues": bson.M{"$in": issueIDs},
+			}}
+		}
+		if _, err := database.DB.Collection(GetUserService().Collection).UpdateOne(ctx, bson.M{"_id": memberId}, userUpdate); err != nil {
+			return fmt.Errorf("failed to unlink project from member: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		log.WithError(err).Error("failed to fetch project")
+		if !errors.Is(err, errMemberNotFound) {
+			log.WithError(err).Error("failed to remove member from project")
+		}
 		return err
 	}
 
-	if res.ModifiedCount == 0 {
-		return err
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectId.Hex(),
+		UserID:    userId.Hex(),
+		Message:   "Member has been removed -> " + memberName(memberId),
+		Timestamp: time.Now(),
 	}
-	return nil
+	return GetLogService().CreateLog(&projectLog)
 }
@@ -0,0 +1,239 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const defaultProjectRetentionDays = 30
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+// projectRetention reads PROJECT_RETENTION_DAYS. Zero disables soft delete.
+func projectRetention() time.Duration {
+	days := defaultProjectRetentionDays
+	if v := os.Getenv("PROJECT_RETENTION_DAYS"); v != "" {
+		parsed, err := strconv.Atoi(v)
+		if err != nil {
+			log.Warnf("Invalid PROJECT_RETENTION_DAYS %q, using %d", v, defaultProjectRetentionDays)
+		} else {
+			days = parsed
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// RestoreProject brings back a soft-deleted project before its purge date.
+func (s *ProjectService) RestoreProject(projectID primitive.ObjectID, user *models.User) (*models.Project, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	project, err := s.getProjectById(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin && project.OwnerID != user.ID {
+		return nil, fmt.Errorf("unauthorized: only owner or admin can restore")
+	}
+	if project.DeletedAt == nil {
+		return nil, fmt.Errorf("project is not deleted")
+	}
+	if project.PurgeAt != nil && time.Now().After(*project.PurgeAt) {
+		return nil, fmt.Errorf("restore window has expired")
+	}
+
+	if err := reserveProjectSlot(ctx, project.OwnerID); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$unset": bson.M{"deleted_at": "", "purge_at": ""}}
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": projectID}, update); err != nil {
+		reduceProjectSize(project.OwnerID)
+		log.WithError(err).Error("Failed to restore project")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    user.ID.Hex(),
+		Message:   "Project has been restored",
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	project.DeletedAt = nil
+	project.PurgeAt = nil
+	return project, nil
+}
+
+// GetDeletedProjects lists the soft-deleted projects owned by the user that can
+// still be restored.
+func (s *ProjectService) GetDeletedProjects(userID primitive.ObjectID) ([]*models.Project, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"owner_id": userID,
+		"purge_at": bson.M{"$gt": time.Now()},
+	}
+	opts := options.Find().SetSort(bson.D{{Key: "purge_at", Value: 1}})
+
+	cursor, err := collection.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	projects := []*models.Project{}
+	if err := cursor.All(ctx, &projects); err != nil {
+		return nil, fmt.Errorf("decode projects failed: %w", err)
+	}
+	return projects, nil
+}
+
+// PurgeExpiredProjects permanently removes soft-deleted projects whose restore
+// window has passed.
+func (s *ProjectService) PurgeExpiredProjects() (int, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	cursor, err := collection.Find(ctx, bson.M{"purge_at": bson.M{"$lte": time.Now()}})
+	if err != nil {
+		return 0, err
+	}
+	var projects []models.Project
+	if err := cursor.All(ctx, &projects); err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	for i := range projects {
+		if err := s.purgeProject(&projects[i]); err != nil {
+			log.WithError(err).Errorf("Failed to purge project %s", projects[i].ID.Hex())
+			continue
+		}
+		purged++
+	}
+	return purged, nil
+}
+
+// StartProjectPurger runs PurgeExpiredProjects periodically in the background.
+func StartProjectPurger(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			purged, err := GetProjectService().PurgeExpiredProjects()
+			if err != nil {
+				log.WithError(err).Error("Project purge failed")
+				continue
+			}
+			if purged > 0 {
+				log.Infof("Purged %d expired projects", purged)
+			}
+		}
+	}()
+}
+
+// purgeProject deletes a project together with its issues, statuses, roles,
+// invites, logs, comments, history, notifications and webhooks, and removes every user reference to it.
+func (s *ProjectService) purgeProject(project *models.Project) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	db := database.DB
+	projectID := project.ID
+
+	return database.WithTransaction(ctx, func(ctx context.Context) error {
+		issuesColl := db.Collection(GetIssueService().Collection)
+
+		var issueIDs []primitive.ObjectID
+		cursor, err := issuesColl.Find(ctx, bson.M{"project_id": projectID}, options.Find().SetProjection(bson.M{"_id": 1}))
+		if err != nil {
+			return err
+		}
+		for cursor.Next(ctx) {
+			var issue models.Issue
+			if err := cursor.Decode(&issue); err != nil {
+				cursor.Close(ctx)
+				return err
+			}
+			issueIDs = append(issueIDs, issue.ID)
+		}
+		cursor.Close(ctx)
+
+		byProject := bson.M{"project_id": projectID}
+		deletes := []struct {
+			collection *mongo.Collection
+			filter     bson.M
+		}{
+			{issuesColl, byProject},
+			{db.Collection(GetStatusService().Collection), byProject},
+			{db.Collection(GetRoleService().Collection), byProject},
+			{db.Collection(GetRoleDefinitionService().Collection), byProject},
+			{db.Collection(GetWorkflowService().Collection), byProject},
+			{db.Collection(GetCommentService().Collection), byProject},
+			{db.Collection(GetIssueHistoryService().Collection), byProject},
+			{db.Collection("project_invites"), byProject},
+			{db.Collection(GetInboxService().Collection), byProject},
+			{db.Collection(GetWebhookService().Collection), byProject},
+			{db.Collection(GetWebhookService().Deliveries), byProject},
+			// Project logs store the project ID as a hex string.
+			{db.Collection(GetLogService().Collection), bson.M{"project_id": projectID.Hex()}},
+		}
+		for _, d := range deletes {
+			if _, err := d.collection.DeleteMany(ctx, d.filter); err != nil {
+				return fmt.Errorf("failed to delete from %s: %w", d.collection.Name(), err)
+			}
+		}
+
+		usersColl := db.Collection(GetUserService().Collection)
+		pull := bson.M{"$pull": bson.M{
+			"team_projects":  projectID,
+			"owned_projects": projectID,
+		}}
+		if _, err := usersColl.UpdateMany(ctx, bson.M{"$or": []bson.M{
+			{"team_projects": projectID},
+			{"owned_projects": projectID},
+		}}, pull); err != nil {
+			return fmt.Errorf("failed to unlink project from users: %w", err)
+		}
+		if len(issueIDs) > 0 {
+			if _, err := usersColl.UpdateMany(ctx,
+				bson.M{"assigned_issues": bson.M{"$in": issueIDs}},
+				bson.M{"$pull": bson.M{"assigned_issues": bson.M{"$in": issueIDs}}},
+			); err != nil {
+				return fmt.Errorf("failed to unlink issues from users: %w", err)
+			}
+		}
+
+		if _, err := db.Collection(s.Collection).DeleteOne(ctx, bson.M{"_id": projectID}); err != nil {
+			return fmt.Errorf("failed to delete project: %w", err)
+		}
+		return nil
+	})
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/models"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var errProjectArchived = errors.New("project is archived and read-only")
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func (s *ProjectService) getProjectById(ctx context.Context, projectID primitive.ObjectID) (*models.Project, error) {
+	collection := database.DB.Collection(s.Collection)
+
+	var project models.Project
+	if err := collection.FindOne(ctx, bson.M{"_id": projectID}).Decode(&project); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// EnsureWritable rejects mutations on archived projects.
+func (s *ProjectService) EnsureWritable(projectID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	project, err := s.getProjectById(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if project.DeletedAt != nil {
+		return fmt.Errorf("project not found")
+	}
+	if project.Lifecycle() == models.ProjectArchived {
+		return errProjectArchived
+	}
+	return nil
+}
+
+// UpdateProject edits the name, description, category and tags of a project.
+// It requires project.update (or admin), and archived projects are read-only.
+func (s *ProjectService) UpdateProject(projectID primitive.ObjectID, user *models.User, req *request.ProjectUpdateRequest) (*models.Project, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	project, err := s.getProjectById(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.DeletedAt != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	if !user.IsAdmin {
+		if err := GetRoleService().Authorize(user.ID, projectID, models.PermProjectUpdate); err != nil {
+			return nil, err
+		}
+	}
+	if project.Lifecycle() == models.ProjectArchived {
+		return nil, errProjectArchived
+	}
+
+	set := bson.M{}
+	var changed []string
+
+	if req.Name != nil && *req.Name != project.Name {
+		set["name"] = *req.Name
+		project.Name = *req.Name
+		changed = append(changed, "name")
+	}
+	if req.Description != nil && *req.Description != project.Description {
+		set["description"] = *req.Description
+		project.Description = *req.Description
+		changed = append(changed, "description")
+	}
+	if req.Category != nil && *req.Category != project.Category {
+		set["category"] = *req.Category
+		project.Category = *req.Category
+		changed = append(changed, "category")
+	}
+	if req.Tags != nil {
+		set["tags"] = *req.Tags
+		project.Tags = *req.Tags
+		changed = append(changed, "tags")
+	}
+
+	if len(set) == 0 {
+		return project, nil
+	}
+
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": projectID}, bson.M{"$set": set}); err != nil {
+		log.WithError(err).Error("Failed to update project")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    user.ID.Hex(),
+		Message:   "Project has been updated: " + strings.Join(changed, ", "),
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// SetProjectStatus moves a project through its lifecycle. It requires
+// project.update (or admin).
+func (s *ProjectService) SetProjectStatus(projectID primitive.ObjectID, user *models.User, status models.ProjectStatus) (*models.Project, error) {
+	if !status.IsValid() {
+		return nil, fmt.Errorf("invalid project status: %s", status)
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	project, err := s.getProjectById(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if project.DeletedAt != nil {
+		return nil, fmt.Errorf("project not found")
+	}
+	if !user.IsAdmin {
+		if err := GetRoleService().Authorize(user.ID, projectID, models.PermProjectUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	previous := project.Lifecycle()
+	if previous == status {
+		return project, nil
+	}
+
+	if _, err := collection.UpdateOne(ctx, bson.M{"_id": projectID}, bson.M{"$set": bson.M{"status": status}}); err != nil {
+		log.WithError(err).Error("Failed to update project status")
+		return nil, err
+	}
+
+	var message string
+	switch {
+	case status == models.ProjectArchived:
+		message = "Project has been archived"
+	case previous == models.ProjectArchived:
+		message = fmt.Sprintf("Project has been unarchived (%s)", status)
+	default:
+		message = fmt.Sprintf("Project status changed %s → %s", previous, status)
+	}
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    user.ID.Hex(),
+		Message:   message,
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	project.Status = status
+	return project, nil
+}
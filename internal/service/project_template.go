@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type ProjectTemplateService struct {
+	Collection string
+}
+
+var projectTemplateService *ProjectTemplateService
+
+var templateKeyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{1,39}$`)
+
+// builtinTemplates are available to every user and cannot be changed.
+var builtinTemplates = []models.ProjectTemplate{
+	{
+		Key:         "kanban",
+		Name:        "Kanban",
+		Description: "Continuous flow board with free movement between columns.",
+		Statuses: []string{
+			string(models.TODO), string(models.IN_PROGRESS), string(models.REVIEW),
+			string(models.DONE), string(models.BLOCKED),
+		},
+	},
+	{
+		Key:         "scrum",
+		Name:        "Scrum",
+		Description: "Sprint board where work goes through review before it is done.",
+		Statuses: []string{
+			string(models.TODO), string(models.IN_PROGRESS), string(models.REVIEW), string(models.DONE),
+		},
+		Tags: []string{"story", "task", "spike"},
+		Transitions: []models.TemplateTransition{
+			{From: string(models.TODO), To: string(models.IN_PROGRESS), RequiredFields: []string{models.FieldAssignee}},
+			{From: string(models.IN_PROGRESS), To: string(models.TODO)},
+			{From: string(models.IN_PROGRESS), To: string(models.REVIEW)},
+			{From: string(models.REVIEW), To: string(models.IN_PROGRESS)},
+			{From: string(models.REVIEW), To: string(models.DONE)},
+			{From: string(models.DONE), To: string(models.IN_PROGRESS)},
+		},
+	},
+	{
+		Key:         "bug_triage",
+		Name:        "Bug triage",
+		Description: "Bug tracking where maintainers triage reports and fixes are verified.",
+		Statuses:    []string{"NEW", "TRIAGED", string(models.IN_PROGRESS), "FIXED", "VERIFIED", "WONT_FIX"},
+		Tags:        []string{"bug", "regression", "crash"},
+		Transitions: []models.TemplateTransition{
+			{From: "NEW", To: "TRIAGED", AllowedRoles: []string{string(models.RoleMaintainer)}},
+			{From: "NEW", To: "WONT_FIX", RequiredFields: []string{models.FieldResolution}, AllowedRoles: []string{string(models.RoleMaintainer)}},
+			{From: "TRIAGED", To: string(models.IN_PROGRESS), RequiredFields: []string{models.FieldAssignee}},
+			{From: string(models.IN_PROGRESS), To: "FIXED", RequiredFields: []string{models.FieldResolution}},
+			{From: "FIXED", To: "VERIFIED"},
+			{From: "FIXED", To: string(models.IN_PROGRESS)},
+			{From: "VERIFIED", To: string(models.IN_PROGRESS)},
+			{From: "WONT_FIX", To: "TRIAGED", AllowedRoles: []string{string(models.RoleMaintainer)}},
+		},
+	},
+}
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetProjectTemplateService() *ProjectTemplateService {
+	if projectTemplateService == nil {
+		projectTemplateService = &ProjectTemplateService{Collection: "project_templates"}
+	}
+	return projectTemplateService
+}
+
+// GetTemplates lists the built-in templates followed by the custom ones.
+func (s *ProjectTemplateService) GetTemplates() ([]*models.ProjectTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	templates := make([]*models.ProjectTemplate, 0, len(builtinTemplates))
+	for _, t := range builtinTemplates {
+		t.BuiltIn = true
+		templates = append(templates, &t)
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	var custom []*models.ProjectTemplate
+	if err := cursor.All(ctx, &custom); err != nil {
+		return nil, err
+	}
+
+	return append(templates, custom...), nil
+}
+
+// GetTemplate finds a built-in or custom template by key.
+func (s *ProjectTemplateService) GetTemplate(ctx context.Context, key string) (*models.ProjectTemplate, error) {
+	for _, t := range builtinTemplates {
+		if t.Key == key {
+			t.BuiltIn = true
+			return &t, nil
+		}
+	}
+
+	var template models.ProjectTemplate
+	if err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"key": key}).Decode(&template); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("project template %s not found", key)
+		}
+		return nil, err
+	}
+	return &template, nil
+}
+
+// CreateTemplate stores a custom template.
+func (s *ProjectTemplateService) CreateTemplate(template *models.ProjectTemplate, userID primitive.ObjectID) (*models.ProjectTemplate, error) {
+	if err := validateTemplate(template); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := database.DB.Collection(s.Collection)
+	count, err := collection.CountDocuments(ctx, bson.M{"key": template.Key})
+	if err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, fmt.Errorf("a template with key %s already exists", template.Key)
+	}
+
+	template.ID = primitive.NewObjectID()
+	template.BuiltIn = false
+	template.CreatedBy = userID
+	template.CreatedAt = time.Now()
+
+	if _, err := collection.InsertOne(ctx, template); err != nil {
+		log.WithError(err).Error("failed to insert project template")
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s *ProjectTemplateService) DeleteTemplate(templateID primitive.ObjectID) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	res, err := database.DB.Collection(s.Collection).DeleteOne(ctx, bson.M{"_id": templateID})
+	if err != nil {
+		log.WithError(err).Error("failed to delete project template")
+		return err
+	}
+	if res.DeletedCount == 0 {
+		return fmt.Errorf("project template not found")
+	}
+	return nil
+}
+
+// applyTemplate seeds the statuses and workflow of a newly inserted project.
+// It must run inside the transaction that created the project.
+func applyTemplate(ctx context.Context, template *models.ProjectTemplate, project *models.Project, userID primitive.ObjectID) error {
+	now := time.Now()
+
+	statusIDs := make(map[string]primitive.ObjectID, len(template.Statuses))
+	statuses := make([]interface{}, 0, len(template.Statuses))
+	for i, name := range template.Statuses {
+		status := models.Status{
+			ID:        primitive.NewObjectID(),
+			ProjectID: project.ID,
+			CreatorID: userID,
+			Name:      name,
+			Position:  i,
+			IssueIDs:  []primitive.ObjectID{},
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		statusIDs[name] = status.ID
+		statuses = append(statuses, status)
+	}
+	if len(statuses) > 0 {
+		if _, err := database.DB.Collection(GetStatusService().Collection).InsertMany(ctx, statuses); err != nil {
+			return fmt.Errorf("failed to seed statuses: %w", err)
+		}
+	}
+
+	if len(template.Transitions) == 0 {
+		return nil
+	}
+
+	transitions := make([]models.WorkflowTransition, 0, len(template.Transitions))
+	for _, t := range template.Transitions {
+		transitions = append(transitions, models.WorkflowTransition{
+			FromStatusID:   statusIDs[t.From],
+			ToStatusID:     statusIDs[t.To],
+			RequiredFields: t.RequiredFields,
+			AllowedRoles:   t.AllowedRoles,
+		})
+	}
+	workflow := models.Workflow{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   project.ID,
+		Transitions: transitions,
+		UpdatedBy:   userID,
+		UpdatedAt:   now,
+	}
+	if _, err := database.DB.Collection(GetWorkflowService().Collection).InsertOne(ctx, workflow); err != nil {
+		return fmt.Errorf("failed to seed workflow: %w", err)
+	}
+	return nil
+}
+
+func validateTemplate(template *models.ProjectTemplate) error {
+	template.Key = strings.TrimSpace(template.Key)
+	template.Name = strings.TrimSpace(template.Name)
+
+	if !templateKeyPattern.MatchString(template.Key) {
+		return fmt.Errorf("template key must be 2-40 lowercase letters, digits, '-' or '_'")
+	}
+	if slices.ContainsFunc(builtinTemplates, func(t models.ProjectTemplate) bool { return t.Key == template.Key }) {
+		return fmt.Errorf("%s is a built-in template", template.Key)
+	}
+	if template.Name == "" {
+		return fmt.Errorf("template name is required")
+	}
+	if len(template.Statuses) == 0 {
+		return fmt.Errorf("a template needs at least one status")
+	}
+
+	for i, name := range template.Statuses {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return fmt.Errorf("status names cannot be empty")
+		}
+		if slices.Contains(template.Statuses[:i], name) {
+			return fmt.Errorf("duplicate status %s", name)
+		}
+		template.Statuses[i] = name
+	}
+
+	for _, t := range template.Transitions {
+		if t.From != "" && !slices.Contains(template.Statuses, t.From) {
+			return fmt.Errorf("transition from unknown status %s", t.From)
+		}
+		if !slices.Contains(template.Statuses, t.To) {
+			return fmt.Errorf("transition to unknown status %s", t.To)
+		}
+		for _, f := range t.RequiredFields {
+			if !slices.Contains(models.TransitionFields, f) {
+				return fmt.Errorf("unknown required field %s", f)
+			}
+		}
+		for _, role := range t.AllowedRoles {
+			if !IsAssignableRole(models.ProjectRole(role)) {
+				return fmt.Errorf("templates can only restrict transitions to MAINTAINER, MEMBER or VIEWER, got %s", role)
+			}
+		}
+	}
+	return nil
+}
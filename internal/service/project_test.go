@@ -0,0 +1,124 @@
+package service
+
+import (
+	"managify/models"
+	"slices"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDeleteMemberCleansIssues(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		ownerID := primitive.NewObjectID()
+		memberID := primitive.NewObjectID()
+		issueID := primitive.NewObjectID()
+		project := models.Project{ID: primitive.NewObjectID(), OwnerID: ownerID, TeamIDs: []primitive.ObjectID{memberID}}
+
+		mt.AddMockResponses(
+			docsReply(t, project), // Authorize
+			docsReply(t, project), // EnsureWritable
+			updateReply(1),        // $pull team
+			mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 0}), // roles
+			docsReply(t, models.Issue{ID: issueID}),                 // assigned issues
+			updateReply(1),                                          // $unset assignee_id
+			updateReply(1),                                          // $pull watchers
+			updateReply(1),                                          // user
+			mtest.CreateSuccessResponse(),                           // commitTransaction
+			docsReply(t, models.User{ID: memberID, FullName: "Member"}),
+			mtest.CreateSuccessResponse(), // project log
+		)
+
+		if err := GetProjectService().DeleteMemberFromProjectById(project.ID, ownerID, memberID); err != nil {
+			t.Fatal(err)
+		}
+
+		var updates []bson.Raw
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "update" {
+				updates = append(updates, e.Command.Lookup("updates", "0").Document())
+			}
+		}
+		if len(updates) != 4 {
+			t.Fatalf("expected 4 updates, got %d: %v", len(updates), sentCommands(mt))
+		}
+		if _, ok := updates[1].Lookup("u", "$unset", "assignee_id").StringValueOK(); !ok {
+			t.Errorf("assignee not unset: %s", updates[1])
+		}
+		if got := updates[2].Lookup("u", "$pull", "watchers").ObjectID(); got != memberID {
+			t.Errorf("watchers pull = %s, want %s", got, memberID)
+		}
+		pulled, _ := updates[3].Lookup("u", "$pull", "assigned_issues", "$in").Array().Values()
+		if len(pulled) != 1 || pulled[0].ObjectID() != issueID {
+			t.Errorf("assigned_issues pull = %v, want [%s]", pulled, issueID)
+		}
+	})
+}
+
+func TestDeleteMemberNotInProject(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		ownerID := primitive.NewObjectID()
+		project := models.Project{ID: primitive.NewObjectID(), OwnerID: ownerID}
+
+		mt.AddMockResponses(
+			docsReply(t, project),
+			docsReply(t, project),
+			updateReply(0),
+			mtest.CreateSuccessResponse(), // abortTransaction
+		)
+
+		err := GetProjectService().DeleteMemberFromProjectById(project.ID, ownerID, primitive.NewObjectID())
+		if err == nil || err.Error() != "member not found in project" {
+			t.Fatalf("err = %v", err)
+		}
+		if slices.Contains(sentCommands(mt), "delete") {
+			t.Error("roles deleted although the member was not removed")
+		}
+	})
+}
+
+func TestGetAssignedIssuesOnlyMemberProjects(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		userID := primitive.NewObjectID()
+		projectID := primitive.NewObjectID()
+
+		mt.AddMockResponses(
+			docsReply(t, models.Project{ID: projectID}),
+			docsReply(t, models.Issue{ID: primitive.NewObjectID(), ProjectID: projectID, AssigneeID: userID}),
+		)
+
+		issues, err := GetIssueService().GetAssignedIssues(userID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 1 {
+			t.Fatalf("got %d issues", len(issues))
+		}
+
+		mt.GetStartedEvent() // member projects
+		find := mt.GetStartedEvent()
+		ids, _ := find.Command.Lookup("filter", "project_id", "$in").Array().Values()
+		if len(ids) != 1 || ids[0].ObjectID() != projectID {
+			t.Errorf("project filter = %v, want [%s]", ids, projectID)
+		}
+	})
+}
+
+func TestGetAssignedIssuesWithoutProjects(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(docsReply(t))
+
+		issues, err := GetIssueService().GetAssignedIssues(primitive.NewObjectID())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(issues) != 0 {
+			t.Fatalf("got %d issues", len(issues))
+		}
+		if got := sentCommands(mt); len(got) != 1 {
+			t.Errorf("commands = %v, want only the project lookup", got)
+		}
+	})
+}
@@ -8,9 +8,8 @@ import (
 	"managify/database"
 	"managify/dto/request"
 	"managify/dto/response"
+	"managify/internal/mailer"
 	"managify/internal/middleware"
-	"net/smtp"
-	"os"
 
 	"managify/models"
 
@@ -57,33 +56,13 @@ func generateToken(n int) (string, error) {
 	return hex.EncodeToString(b), nil
 }
 
-func sendVerificationEmail(email, token string) error {
-
-	from := os.Getenv("SMTP_FROM")
-	pass := os.Getenv("SMTP_PASSWORD")
-	smtpHost := os.Getenv("SMTP_HOST")
-	smtpPort := os.Getenv("SMTP_PORT")
-
-	fmt.Println(from, pass, smtpHost, smtpPort)
-
-	to := email
-	msg := "Subject: Email Verification\n" +
-		"MIME-version: 1.0;\n" +
-		"Content-Type: text/html; charset=\"UTF-8\";\n\n" +
-		"<html>" +
-		"<body>" +
-		"<h2>Verify Your Email</h2>" +
-		"<p>Click the button below to verify your account:</p>" +
-		"<a href='http://localhost:5173/verify?token=" + token + "' " +
-		"style='display:inline-block;padding:10px 20px;background-color:#4CAF50;color:white;text-decoration:none;border-radius:5px;'>Verify Email</a>" +
-		"<p>If you did not create an account, you can ignore this email.</p>" +
-		"</body>" +
-		"</html>"
-
-	addr := smtpHost + ":" + smtpPort
-	return smtp.SendMail(addr,
-		smtp.PlainAuth("", from, pass, smtpHost),
-		from, []string{to}, []byte(msg))
+// sendVerificationEmail queues the verification link for the user.
+func sendVerificationEmail(ctx context.Context, user *models.User, token string) error {
+	return GetMailService().Enqueue(ctx, user.Email, mailer.TemplateVerification, map[string]any{
+		"Name":  user.FullName,
+		"Link":  frontendURL() + "/verify?token=" + token,
+		"Hours": int(verificationTTL().Hours()),
+	})
 }
 
 func (s *UserService) CreateUser(user *models.User) (*models.User, string, error) {
@@ -100,11 +79,10 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, string, error
 	user.Password = string(hashedPassword)
 	user.ID = primitive.NewObjectID()
 
-	verifyToken, err := generateToken(32)
+	verifyToken, err := newVerification(user)
 	if err != nil {
 		return nil, "", err
 	}
-	user.VerificationToken = verifyToken
 	user.IsVerified = false
 
 	_, err = collection.InsertOne(ctx, user)
@@ -119,7 +97,9 @@ func (s *UserService) CreateUser(user *models.User) (*models.User, string, error
 		return nil, "", err
 	}
 
-	go sendVerificationEmail(user.Email, user.VerificationToken)
+	if err := sendVerificationEmail(ctx, user, verifyToken); err != nil {
+		log.WithError(err).Errorf("failed to queue verification email to %s", user.Email)
+	}
 
 	user.Password = ""
 
@@ -131,16 +111,36 @@ func (s *UserService) VerifyEmail(token string) (*models.User, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	if token == "" {
+		return nil, fmt.Errorf("verification link is invalid or has expired")
+	}
+
+	// Links sent before tokens were hashed carry the stored token itself and
+	// have no expiry; they stay valid until used or replaced by a resend.
 	var user models.User
-	err := collection.FindOne(ctx, bson.M{"verificationtoken": token}).Decode(&user)
+	filter := bson.M{"$or": []bson.M{
+		{
+			"verificationtoken":       hashToken(token),
+			"verification_expires_at": bson.M{"$gt": time.Now()},
+		},
+		{
+			"verificationtoken":       token,
+			"verification_expires_at": bson.M{"$exists": false},
+		},
+	}}
+	err := collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
-		fmt.Println("Error finding user with token:", err)
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("verification link is invalid or has expired")
+		}
 		return nil, err
 	}
 
-	update := bson.M{"$set": bson.M{"isverified": true, "verificationtoken": ""}}
-	filter := bson.M{"_id": user.ID}
-	_, err = collection.UpdateOne(ctx, filter, update)
+	update := bson.M{
+		"$set":   bson.M{"isverified": true},
+		"$unset": bson.M{"verificationtoken": "", "verification_expires_at": ""},
+	}
+	_, err = collection.UpdateOne(ctx, bson.M{"_id": user.ID}, update)
 	if err != nil {
 		return nil, err
 	}
@@ -150,43 +150,82 @@ func (s *UserService) VerifyEmail(token string) (*models.User, error) {
 	return &user, nil
 }
 
-func (s *UserService) Login(req *request.UserLoginRequest) (*response.UserLoginResponse, error) {
+func (s *UserService) Login(req *request.UserLoginRequest, info SessionInfo) (*response.UserLoginResponse, error) {
 
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	guard := GetLoginGuard()
+	if err := guard.Check(ctx, req.Email, info.IP); err != nil {
+		log.Warnf("Login blocked for %s from %s: %v", req.Email, info.IP, err)
+		return nil, err
+	}
+
 	var user models.User
 	filter := bson.M{"email": req.Email}
 	err := collection.FindOne(ctx, filter).Decode(&user)
 	if err != nil {
 		log.Warnf("User not found: %s", req.Email)
+		guard.Failure(ctx, req.Email, info.IP, primitive.NilObjectID)
 		return nil, fmt.Errorf("invalid email or password")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password))
 	if err != nil {
+		guard.Failure(ctx, req.Email, info.IP, user.ID)
 		return nil, fmt.Errorf("invalid email or password")
 	}
 
-	tokenString, err := s.CreateToken(&user)
-	if err != nil {
-		return nil, fmt.Errorf("could not generate token")
+	if user.TwoFactorEnabled {
+		challenge, err := GetTwoFactorService().createChallenge(ctx, user.ID)
+		if err != nil {
+			return nil, fmt.Errorf("could not start two-factor login")
+		}
+		log.Infof("Two-factor challenge issued for %s", req.Email)
+		return &response.UserLoginResponse{
+			FullName:          user.FullName,
+			Email:             user.Email,
+			IsVerified:        user.IsVerified,
+			TwoFactorRequired: true,
+			ChallengeToken:    challenge,
+		}, nil
 	}
 
-	resp := &response.UserLoginResponse{
-		FullName: user.FullName,
-		Email:    user.Email,
-		Token:    tokenString,
-	}
-	if !user.IsVerified {
-		go sendVerificationEmail(user.Email, user.VerificationToken)
+	// With two-factor authentication the failures are only forgotten once
+	// the second step succeeds.
+	guard.Success(ctx, req.Email)
+
+	resp, err := s.issueLogin(&user, info)
+	if err != nil {
+		return nil, err
 	}
 
 	log.Infof("User logged in successfully: %s", req.Email)
 	return resp, nil
 }
 
+// issueLogin creates the access and refresh token of a completed login.
+func (s *UserService) issueLogin(user *models.User, info SessionInfo) (*response.UserLoginResponse, error) {
+	tokenString, err := s.CreateToken(user)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token")
+	}
+
+	refreshToken, err := GetAuthService().IssueRefreshToken(user.ID, info)
+	if err != nil {
+		return nil, fmt.Errorf("could not generate token")
+	}
+
+	return &response.UserLoginResponse{
+		FullName:     user.FullName,
+		Email:        user.Email,
+		Token:        tokenString,
+		RefreshToken: refreshToken,
+		IsVerified:   user.IsVerified,
+	}, nil
+}
+
 func (s *UserService) IsUserValid(userId primitive.ObjectID) (bool, error) {
 
 	collection := database.DB.Collection(s.Collection)
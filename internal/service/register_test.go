@@ -0,0 +1,45 @@
+package service
+
+import (
+	"managify/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestVerifyEmailAcceptsLegacyToken(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		user := models.User{ID: primitive.NewObjectID(), VerificationToken: "legacy"}
+		mt.AddMockResponses(docsReply(t, user), updateReply(1))
+
+		verified, err := GetUserService().VerifyEmail("legacy")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verified.IsVerified {
+			t.Error("user was not marked verified")
+		}
+
+		branches, _ := mt.GetStartedEvent().Command.Lookup("filter", "$or").Array().Values()
+		if len(branches) != 2 {
+			t.Fatalf("filter has %d branches, want hashed and legacy", len(branches))
+		}
+		legacy := branches[1].Document()
+		if legacy.Lookup("verificationtoken").StringValue() != "legacy" ||
+			legacy.Lookup("verification_expires_at", "$exists").Boolean() {
+			t.Errorf("legacy branch = %s", legacy)
+		}
+	})
+}
+
+func TestVerifyEmailRejectsEmptyToken(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		if _, err := GetUserService().VerifyEmail(""); err == nil {
+			t.Fatal("expected an empty token to be rejected")
+		}
+		if got := sentCommands(mt); len(got) != 0 {
+			t.Errorf("commands = %v, want none", got)
+		}
+	})
+}
@@ -12,6 +12,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type RoleService struct {
@@ -35,7 +36,17 @@ func GetRoleService() *RoleService {
 	return roleService
 }
 
-func (s *RoleService) AddRole(userId primitive.ObjectID, projectId primitive.ObjectID, roleName string) (*models.Role, error) {
+type ProjectMember struct {
+	UserID   primitive.ObjectID `json:"user_id"`
+	FullName string             `json:"full_name"`
+	Email    string             `json:"email"`
+	Role     models.ProjectRole `json:"role"`
+}
+
+// AddRole gives a team member a role in the project, replacing the role they
+// had before. roleName is either a built-in role or the name of one of the
+// project's RoleDefinitions. actorID is the user performing the change.
+func (s *RoleService) AddRole(actorID, userId, projectId primitive.ObjectID, roleName string) (*models.Role, error) {
 
 	ps := GetProjectService()
 
@@ -43,29 +54,53 @@ func (s *RoleService) AddRole(userId primitive.ObjectID, projectId primitive.Obj
 	if err != nil || !projectValid {
 		return nil, err
 	}
+	if err := ps.EnsureWritable(projectId); err != nil {
+		return nil, err
+	}
 
-	role := &models.Role{
-		ID:        primitive.NewObjectID(),
-		UserID:    userId,
-		ProjectID: projectId,
-		RoleName:  roleName,
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	set := bson.M{"role": roleName}
+	unset := bson.M{"definition_id": ""}
+	if !IsAssignableRole(models.ProjectRole(roleName)) {
+		definition, err := GetRoleDefinitionService().getDefinitionByName(ctx, projectId, roleName)
+		if err != nil {
+			if err == mongo.ErrNoDocuments {
+				return nil, fmt.Errorf("invalid role: %s", roleName)
+			}
+			return nil, err
+		}
+		roleName = definition.Name
+		set = bson.M{"role": roleName, "definition_id": definition.ID}
+		unset = nil
 	}
 
-	exists, err := ps.IsUserInProject(role.UserID, role.ProjectID)
+	current, err := s.GetUserRole(userId, projectId)
 	if err != nil {
+		if IsNotProjectMember(err) {
+			return nil, fmt.Errorf("user is not part of the project")
+		}
 		return nil, err
 	}
-	if !exists {
-		return nil, fmt.Errorf("user is not part of the project")
+	if current == models.RoleOwner {
+		return nil, fmt.Errorf("the project owner's role cannot be changed")
 	}
 
-	collection := database.DB.Collection(s.Collection)
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	filter := bson.M{"user_id": userId, "project_id": projectId}
+	update := bson.M{
+		"$set":         set,
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+	}
+	if unset != nil {
+		update["$unset"] = unset
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
 
-	_, err = collection.InsertOne(ctx, role)
-	if err != nil {
-		log.WithError(err).Error("failed to insert role")
+	var role models.Role
+	if err := collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&role); err != nil {
+		log.WithError(err).Error("failed to upsert role")
 		return nil, err
 	}
 
@@ -73,22 +108,45 @@ func (s *RoleService) AddRole(userId primitive.ObjectID, projectId primitive.Obj
 	projectLog := models.ProjectLog{
 		ID:        projectLogId,
 		ProjectID: role.ProjectID.Hex(),
-		UserID:    userId.Hex(),
-		Message:   "Role Has Been Assigned -> " + roleName,
+		UserID:    actorID.Hex(),
+		Message:   fmt.Sprintf("Role Has Been Assigned -> %s to %s (was %s)", roleName, memberName(userId), current),
 		Timestamp: time.Now(),
 	}
 	if err := GetLogService().CreateLog(&projectLog); err != nil {
 		return nil, err
 	}
-	return role, nil
+
+	GetNotificationService().Notify(Notification{
+		UserID:    userId,
+		ActorID:   actorID,
+		ProjectID: projectId,
+		Event:     models.EventRoleAssigned,
+		Title:     fmt.Sprintf("You are now %s", roleName),
+		Message:   fmt.Sprintf("%s made you %s in %s.", displayName(ctx, actorID), roleName, activeProjectName(ctx, projectId)),
+		Link:      projectLink(projectId),
+	})
+	return &role, nil
 }
 
-func (s *RoleService) DeleteRole(deleteId primitive.ObjectID) error {
+// DeleteRole removes a role assignment of the given project, returning the
+// member to the default MEMBER role.
+func (s *RoleService) DeleteRole(deleteId, projectId, actorID primitive.ObjectID) error {
 	collection := database.DB.Collection(s.Collection)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	res, err := collection.DeleteOne(ctx, bson.M{"_id": deleteId})
+	var role models.Role
+	if err := collection.FindOne(ctx, bson.M{"_id": deleteId, "project_id": projectId}).Decode(&role); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("role not found")
+		}
+		return err
+	}
+	if err := GetProjectService().EnsureWritable(role.ProjectID); err != nil {
+		return err
+	}
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": deleteId, "project_id": projectId})
 	if err != nil {
 		log.WithError(err).Error("failed to delete role")
 		return err
@@ -98,7 +156,72 @@ func (s *RoleService) DeleteRole(deleteId primitive.ObjectID) error {
 		return fmt.Errorf("role not found")
 	}
 
-	return nil
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectId.Hex(),
+		UserID:    actorID.Hex(),
+		Message:   "Role Has Been Removed -> " + role.RoleName,
+		Timestamp: time.Now(),
+	}
+	return GetLogService().CreateLog(&projectLog)
+}
+
+// GetProjectMembers lists the owner and team of a project with their roles.
+func (s *RoleService) GetProjectMembers(projectID primitive.ObjectID) ([]*ProjectMember, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var project models.Project
+	projectsColl := database.DB.Collection(GetProjectService().Collection)
+	if err := projectsColl.FindOne(ctx, bson.M{"_id": projectID, "deleted_at": bson.M{"$exists": false}}).Decode(&project); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("project not found")
+		}
+		return nil, err
+	}
+
+	roles := map[primitive.ObjectID]models.ProjectRole{}
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, bson.M{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+	var assigned []models.Role
+	if err := cursor.All(ctx, &assigned); err != nil {
+		return nil, err
+	}
+	for _, r := range assigned {
+		roles[r.UserID] = models.ProjectRole(r.RoleName)
+	}
+
+	memberIDs := append([]primitive.ObjectID{project.OwnerID}, project.TeamIDs...)
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	userOpts := options.Find().SetProjection(bson.M{"full_name": 1, "email": 1})
+	userCursor, err := usersColl.Find(ctx, bson.M{"_id": bson.M{"$in": memberIDs}}, userOpts)
+	if err != nil {
+		return nil, err
+	}
+	var users []models.User
+	if err := userCursor.All(ctx, &users); err != nil {
+		return nil, err
+	}
+
+	members := make([]*ProjectMember, 0, len(users))
+	for _, u := range users {
+		role := models.RoleMember
+		if u.ID == project.OwnerID {
+			role = models.RoleOwner
+		} else if r, ok := roles[u.ID]; ok {
+			role = r
+		}
+		members = append(members, &ProjectMember{
+			UserID:   u.ID,
+			FullName: u.FullName,
+			Email:    u.Email,
+			Role:     role,
+		})
+	}
+
+	return members, nil
 }
 
 func (s *ProjectService) IsOwner(ownerId, projectId primitive.ObjectID) (bool, error) {
@@ -108,8 +231,9 @@ func (s *ProjectService) IsOwner(ownerId, projectId primitive.ObjectID) (bool, e
 
 	var role models.Role
 	err := collection.FindOne(ctx, bson.M{
-		"owner_id": ownerId,
-		"_id":      projectId,
+		"owner_id":   ownerId,
+		"_id":        projectId,
+		"deleted_at": bson.M{"$exists": false},
 	}).Decode(&role)
 
 	if err != nil {
@@ -121,3 +245,11 @@ func (s *ProjectService) IsOwner(ownerId, projectId primitive.ObjectID) (bool, e
 
 	return true, nil
 }
+
+func memberName(userID primitive.ObjectID) string {
+	user, err := GetUserService().GetUserById(userID.Hex())
+	if err != nil {
+		return userID.Hex()
+	}
+	return user.FullName
+}
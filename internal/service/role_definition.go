@@ -0,0 +1,284 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type RoleDefinitionService struct {
+	Collection string
+}
+
+var roleDefinitionService *RoleDefinitionService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetRoleDefinitionService() *RoleDefinitionService {
+	if roleDefinitionService == nil {
+		roleDefinitionService = &RoleDefinitionService{Collection: "role_definitions"}
+	}
+	return roleDefinitionService
+}
+
+// CreateDefinition adds a custom role to the project.
+func (s *RoleDefinitionService) CreateDefinition(actorID, projectID primitive.ObjectID, req *request.RoleDefinitionRequest) (*models.RoleDefinition, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	name, permissions, err := parseDefinition(req)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := s.ensureNameFree(ctx, projectID, primitive.NilObjectID, name); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	definition := models.RoleDefinition{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   projectID,
+		Name:        name,
+		Permissions: permissions,
+		CreatedBy:   actorID,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if _, err := collection.InsertOne(ctx, definition); err != nil {
+		log.WithError(err).Error("failed to insert role definition")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    actorID.Hex(),
+		Message:   fmt.Sprintf("Role Has Been Defined -> %s (%s)", name, joinPermissions(permissions)),
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return &definition, nil
+}
+
+func (s *RoleDefinitionService) GetDefinitions(projectID primitive.ObjectID) ([]*models.RoleDefinition, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	definitions := []*models.RoleDefinition{}
+	if err := cursor.All(ctx, &definitions); err != nil {
+		return nil, err
+	}
+	return definitions, nil
+}
+
+// UpdateDefinition renames a custom role or replaces its permissions. Members
+// holding the role pick up the new permissions immediately.
+func (s *RoleDefinitionService) UpdateDefinition(actorID, projectID, definitionID primitive.ObjectID, req *request.RoleDefinitionRequest) (*models.RoleDefinition, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	name, permissions, err := parseDefinition(req)
+	if err != nil {
+		return nil, err
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	current, err := s.getDefinition(ctx, projectID, definitionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("role definition not found")
+		}
+		return nil, err
+	}
+	if err := s.ensureNameFree(ctx, projectID, definitionID, name); err != nil {
+		return nil, err
+	}
+
+	update := bson.M{"$set": bson.M{
+		"name":        name,
+		"permissions": permissions,
+		"updated_at":  time.Now(),
+	}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var definition models.RoleDefinition
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"_id": definitionID, "project_id": projectID}, update, opts).Decode(&definition); err != nil {
+		log.WithError(err).Error("failed to update role definition")
+		return nil, err
+	}
+
+	if current.Name != name {
+		rolesColl := database.DB.Collection(GetRoleService().Collection)
+		if _, err := rolesColl.UpdateMany(ctx, bson.M{"definition_id": definitionID}, bson.M{"$set": bson.M{"role": name}}); err != nil {
+			log.WithError(err).Error("failed to rename role assignments")
+			return nil, err
+		}
+		if err := GetWorkflowService().RenameRole(ctx, projectID, current.Name, name); err != nil {
+			log.WithError(err).Error("failed to rename role in workflow")
+			return nil, err
+		}
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    actorID.Hex(),
+		Message:   fmt.Sprintf("Role Has Been Redefined -> %s (%s)", name, joinPermissions(permissions)),
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return &definition, nil
+}
+
+// DeleteDefinition removes a custom role. Members holding it fall back to the
+// default MEMBER role.
+func (s *RoleDefinitionService) DeleteDefinition(actorID, projectID, definitionID primitive.ObjectID) error {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return err
+	}
+
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	definition, err := s.getDefinition(ctx, projectID, definitionID)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("role definition not found")
+		}
+		return err
+	}
+
+	err = database.WithTransaction(ctx, func(ctx context.Context) error {
+		if _, err := collection.DeleteOne(ctx, bson.M{"_id": definitionID, "project_id": projectID}); err != nil {
+			return err
+		}
+		rolesColl := database.DB.Collection(GetRoleService().Collection)
+		_, err := rolesColl.DeleteMany(ctx, bson.M{"definition_id": definitionID})
+		return err
+	})
+	if err != nil {
+		log.WithError(err).Error("failed to delete role definition")
+		return err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    actorID.Hex(),
+		Message:   "Role Definition Has Been Removed -> " + definition.Name,
+		Timestamp: time.Now(),
+	}
+	return GetLogService().CreateLog(&projectLog)
+}
+
+func (s *RoleDefinitionService) getDefinition(ctx context.Context, projectID, definitionID primitive.ObjectID) (*models.RoleDefinition, error) {
+	var definition models.RoleDefinition
+	err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": definitionID, "project_id": projectID}).Decode(&definition)
+	if err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+// getDefinitionByName looks up a custom role of the project, ignoring case.
+func (s *RoleDefinitionService) getDefinitionByName(ctx context.Context, projectID primitive.ObjectID, name string) (*models.RoleDefinition, error) {
+	filter := bson.M{"project_id": projectID, "name": strings.TrimSpace(name)}
+	opts := options.FindOne().SetCollation(&options.Collation{Locale: "en", Strength: 2})
+
+	var definition models.RoleDefinition
+	if err := database.DB.Collection(s.Collection).FindOne(ctx, filter, opts).Decode(&definition); err != nil {
+		return nil, err
+	}
+	return &definition, nil
+}
+
+func (s *RoleDefinitionService) ensureNameFree(ctx context.Context, projectID, selfID primitive.ObjectID, name string) error {
+	existing, err := s.getDefinitionByName(ctx, projectID, name)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil
+		}
+		return err
+	}
+	if existing.ID != selfID {
+		return fmt.Errorf("a role named %s already exists", existing.Name)
+	}
+	return nil
+}
+
+func parseDefinition(req *request.RoleDefinitionRequest) (string, []models.Permission, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return "", nil, fmt.Errorf("role name is required")
+	}
+	if IsBuiltinRole(name) {
+		return "", nil, fmt.Errorf("%s is a built-in role", strings.ToUpper(name))
+	}
+
+	permissions := make([]models.Permission, 0, len(req.Permissions))
+	for _, p := range req.Permissions {
+		perm := models.Permission(strings.TrimSpace(p))
+		if !perm.IsValid() {
+			return "", nil, fmt.Errorf("invalid permission: %s", p)
+		}
+		if perm == models.PermProjectDelete {
+			return "", nil, fmt.Errorf("%s is reserved for the project owner", perm)
+		}
+		if !slices.Contains(permissions, perm) {
+			permissions = append(permissions, perm)
+		}
+	}
+	if len(permissions) == 0 {
+		return "", nil, fmt.Errorf("at least one permission is required")
+	}
+	return name, permissions, nil
+}
+
+func joinPermissions(permissions []models.Permission) string {
+	names := make([]string, len(permissions))
+	for i, p := range permissions {
+		names[i] = string(p)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultSearchLimit = 10
+	maxSearchLimit     = 50
+	snippetLength      = 160
+)
+
+type SearchService struct{}
+
+var searchService *SearchService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetSearchService() *SearchService {
+	if searchService == nil {
+		searchService = &SearchService{}
+	}
+	return searchService
+}
+
+type SearchHit struct {
+	ID        primitive.ObjectID `json:"id"`
+	ProjectID primitive.ObjectID `json:"project_id"`
+	IssueID   primitive.ObjectID `json:"issue_id,omitempty"`
+	Title     string             `json:"title"`
+	Snippet   string             `json:"snippet"`
+	Score     float64            `json:"score"`
+}
+
+type SearchResult struct {
+	Projects []SearchHit `json:"projects"`
+	Issues   []SearchHit `json:"issues"`
+	Comments []SearchHit `json:"comments"`
+}
+
+// Search runs a ranked text search over projects, issues and comments. Only
+// documents from projects the user owns or belongs to are returned.
+func (s *SearchService) Search(userID primitive.ObjectID, query string, limit int) (*SearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, fmt.Errorf("search query is required")
+	}
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	if limit > maxSearchLimit {
+		limit = maxSearchLimit
+	}
+
+	result := &SearchResult{
+		Projects: []SearchHit{},
+		Issues:   []SearchHit{},
+		Comments: []SearchHit{},
+	}
+
+	projectIDs, err := GetProjectService().GetMemberProjectIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(projectIDs) == 0 {
+		return result, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().
+		SetProjection(bson.M{"score": bson.M{"$meta": "textScore"}}).
+		SetSort(bson.D{{Key: "score", Value: bson.M{"$meta": "textScore"}}}).
+		SetLimit(int64(limit))
+
+	projectsColl := database.DB.Collection(GetProjectService().Collection)
+	projectCursor, err := projectsColl.Find(ctx, bson.M{
+		"$text": bson.M{"$search": query},
+		"_id":   bson.M{"$in": projectIDs},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for projectCursor.Next(ctx) {
+		var doc struct {
+			models.Project `bson:",inline"`
+			Score          float64 `bson:"score"`
+		}
+		if err := projectCursor.Decode(&doc); err != nil {
+			projectCursor.Close(ctx)
+			return nil, err
+		}
+		result.Projects = append(result.Projects, SearchHit{
+			ID:        doc.ID,
+			ProjectID: doc.ID,
+			Title:     doc.Name,
+			Snippet:   snippet(doc.Description),
+			Score:     doc.Score,
+		})
+	}
+	err = projectCursor.Err()
+	projectCursor.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	issuesColl := database.DB.Collection(GetIssueService().Collection)
+	issueCursor, err := issuesColl.Find(ctx, bson.M{
+		"$text":      bson.M{"$search": query},
+		"project_id": bson.M{"$in": projectIDs},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	for issueCursor.Next(ctx) {
+		var doc struct {
+			models.Issue `bson:",inline"`
+			Score        float64 `bson:"score"`
+		}
+		if err := issueCursor.Decode(&doc); err != nil {
+			issueCursor.Close(ctx)
+			return nil, err
+		}
+		result.Issues = append(result.Issues, SearchHit{
+			ID:        doc.ID,
+			ProjectID: doc.ProjectID,
+			IssueID:   doc.ID,
+			Title:     doc.Title,
+			Snippet:   snippet(doc.Description),
+			Score:     doc.Score,
+		})
+	}
+	err = issueCursor.Err()
+	issueCursor.Close(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	commentsColl := database.DB.Collection(GetCommentService().Collection)
+	commentCursor, err := commentsColl.Find(ctx, bson.M{
+		"$text":      bson.M{"$search": query},
+		"project_id": bson.M{"$in": projectIDs},
+		"is_deleted": false,
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer commentCursor.Close(ctx)
+	for commentCursor.Next(ctx) {
+		var doc struct {
+			models.Comment `bson:",inline"`
+			Score          float64 `bson:"score"`
+		}
+		if err := commentCursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		result.Comments = append(result.Comments, SearchHit{
+			ID:        doc.ID,
+			ProjectID: doc.ProjectID,
+			IssueID:   doc.IssueID,
+			Snippet:   snippet(doc.Body),
+			Score:     doc.Score,
+		})
+	}
+	if err := commentCursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func snippet(text string) string {
+	runes := []rune(text)
+	if len(runes) <= snippetLength {
+		return text
+	}
+	return string(runes[:snippetLength]) + "…"
+}
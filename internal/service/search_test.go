@@ -0,0 +1,35 @@
+package service
+
+import (
+	"managify/models"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestSearchReturnsCursorErrors(t *testing.T) {
+	for _, stage := range []string{"projects", "issues", "comments"} {
+		t.Run(stage, func(t *testing.T) {
+			withMockDB(t, func(mt *mtest.T) {
+				responses := []bson.D{docsReply(t, models.Project{ID: primitive.NewObjectID()})}
+				for _, s := range []string{"projects", "issues", "comments"} {
+					if s == stage {
+						responses = append(responses,
+							mtest.CreateCursorResponse(1, "test.mock", mtest.FirstBatch),
+							mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 43, Message: "cursor killed"}),
+						)
+						break
+					}
+					responses = append(responses, docsReply(t))
+				}
+				mt.AddMockResponses(responses...)
+
+				if _, err := GetSearchService().Search(primitive.NewObjectID(), "login", 10); err == nil {
+					t.Fatal("expected the cursor error to be returned")
+				}
+			})
+		})
+	}
+}
@@ -4,12 +4,15 @@ import (
 	"context"
 	"fmt"
 	"managify/database"
+	"managify/internal/realtime"
 	"managify/models"
 	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type StatusService struct {
@@ -40,6 +43,9 @@ func (s *StatusService) CreateStatus(status *models.Status) (*models.Status, err
 	if err != nil || !projectValid {
 		return nil, err
 	}
+	if err := ps.EnsureWritable(status.ProjectID); err != nil {
+		return nil, err
+	}
 
 	exists, err := ps.IsUserInProject(status.CreatorID, status.ProjectID)
 	if err != nil {
@@ -53,6 +59,13 @@ func (s *StatusService) CreateStatus(status *models.Status) (*models.Status, err
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
+	position, err := collection.CountDocuments(ctx, bson.M{"project_id": status.ProjectID})
+	if err != nil {
+		return nil, err
+	}
+
+	status.Position = int(position)
+	status.IssueIDs = []primitive.ObjectID{}
 	status.CreatedAt = time.Now()
 	status.UpdatedAt = time.Now()
 
@@ -77,6 +90,8 @@ func (s *StatusService) CreateStatus(status *models.Status) (*models.Status, err
 	if err := GetLogService().CreateLog(&projectLog); err != nil {
 		return nil, err
 	}
+
+	publishEvent(status.ProjectID, status.CreatorID, realtime.StatusCreated, status)
 	return status, nil
 }
 
@@ -93,8 +108,29 @@ func (s *StatusService) DeleteStatus(deleteId primitive.ObjectID, projectId prim
 	if !exists {
 		return fmt.Errorf("user is not part of the project")
 	}
+	if err := ps.EnsureWritable(projectId); err != nil {
+		return err
+	}
 
-	res, err := collection.DeleteOne(ctx, bson.M{"_id": deleteId})
+	issueCount, err := database.DB.Collection(GetIssueService().Collection).CountDocuments(ctx, bson.M{"status_id": deleteId})
+	if err != nil {
+		return err
+	}
+	if issueCount > 0 {
+		return fmt.Errorf("status still has %d issues, move them to another status first", issueCount)
+	}
+
+	// Dropping the transitions instead could leave the workflow empty, which
+	// would silently allow every move.
+	inWorkflow, err := GetWorkflowService().ReferencesStatus(ctx, projectId, deleteId)
+	if err != nil {
+		return err
+	}
+	if inWorkflow {
+		return fmt.Errorf("status is used by the project workflow, remove its transitions first")
+	}
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": deleteId, "project_id": projectId})
 	if err != nil {
 		log.WithError(err).Error("failed to delete status")
 		return err
@@ -104,6 +140,7 @@ func (s *StatusService) DeleteStatus(deleteId primitive.ObjectID, projectId prim
 		return fmt.Errorf("status not found")
 	}
 
+	publishEvent(projectId, userId, realtime.StatusDeleted, map[string]any{"status_id": deleteId})
 	return nil
 }
 
@@ -112,7 +149,8 @@ func (s *StatusService) GetStatusesByProjectId(projectID primitive.ObjectID) ([]
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
-	cursor, err := collection.Find(ctx, bson.M{"project_id": projectID})
+	opts := options.Find().SetSort(bson.D{{Key: "position", Value: 1}, {Key: "created_at", Value: 1}})
+	cursor, err := collection.Find(ctx, bson.M{"project_id": projectID}, opts)
 	if err != nil {
 		return nil, err
 	}
@@ -133,3 +171,19 @@ func (s *StatusService) GetStatusesByProjectId(projectID primitive.ObjectID) ([]
 
 	return statuses, nil
 }
+
+func (s *StatusService) GetStatusById(statusID primitive.ObjectID) (*models.Status, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var status models.Status
+	if err := collection.FindOne(ctx, bson.M{"_id": statusID}).Decode(&status); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("status not found")
+		}
+		return nil, err
+	}
+
+	return &status, nil
+}
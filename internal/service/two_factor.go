@@ -0,0 +1,339 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/response"
+	"managify/internal/totp"
+	"managify/models"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// challengeTTL is how long the second login step can be completed.
+	challengeTTL = 5 * time.Minute
+	// maxChallengeAttempts is how many codes can be tried per challenge.
+	maxChallengeAttempts = 5
+	recoveryCodeCount    = 10
+)
+
+// TwoFactorService manages TOTP enrollment and the second login step.
+type TwoFactorService struct {
+	Collection string
+}
+
+// TwoFactorSetup is shown once while enrolling an authenticator app.
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+var twoFactorService *TwoFactorService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetTwoFactorService() *TwoFactorService {
+	if twoFactorService == nil {
+		twoFactorService = &TwoFactorService{Collection: "login_challenges"}
+	}
+	return twoFactorService
+}
+
+// totpIssuer reads TOTP_ISSUER, the name authenticator apps show.
+func totpIssuer() string {
+	if issuer := os.Getenv("TOTP_ISSUER"); issuer != "" {
+		return issuer
+	}
+	return "Managify"
+}
+
+// Setup starts enrollment with a new secret. Two-factor login is enabled once
+// a code of the secret is confirmed through Enable.
+func (s *TwoFactorService) Setup(userID primitive.ObjectID) (*TwoFactorSetup, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"two_factor_pending_secret": secret}}); err != nil {
+		log.WithError(err).Error("failed to store pending two-factor secret")
+		return nil, err
+	}
+
+	return &TwoFactorSetup{Secret: secret, URI: totp.URI(totpIssuer(), user.Email, secret)}, nil
+}
+
+// Enable verifies a code of the pending secret, turns on two-factor login and
+// returns the recovery codes. They are not stored in plain text and cannot be
+// shown again.
+func (s *TwoFactorService) Enable(userID primitive.ObjectID, code string) ([]string, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is already enabled")
+	}
+	if user.TwoFactorPendingSecret == "" {
+		return nil, fmt.Errorf("start the two-factor setup first")
+	}
+
+	step, ok := totp.Validate(user.TwoFactorPendingSecret, code, time.Now())
+	if !ok {
+		return nil, fmt.Errorf("invalid authentication code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"two_factor_enabled":   true,
+			"two_factor_secret":    user.TwoFactorPendingSecret,
+			"two_factor_last_step": step,
+			"recovery_codes":       hashes,
+		},
+		"$unset": bson.M{"two_factor_pending_secret": ""},
+	}
+	filter := bson.M{"_id": userID, "two_factor_pending_secret": user.TwoFactorPendingSecret}
+	res, err := usersColl.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.WithError(err).Error("failed to enable two-factor authentication")
+		return nil, err
+	}
+	if res.ModifiedCount == 0 {
+		return nil, fmt.Errorf("two-factor setup changed, start it again")
+	}
+
+	log.Infof("Two-factor authentication enabled for user %s", userID.Hex())
+	return codes, nil
+}
+
+// Disable turns off two-factor login after checking the password and a code.
+func (s *TwoFactorService) Disable(userID primitive.ObjectID, password, code string) error {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := GetUserService().checkPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+	if !user.TwoFactorEnabled {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		return err
+	}
+
+	update := bson.M{
+		"$set": bson.M{"two_factor_enabled": false},
+		"$unset": bson.M{
+			"two_factor_secret": "", "two_factor_pending_secret": "",
+			"two_factor_last_step": "", "recovery_codes": "",
+		},
+	}
+	if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": userID}, update); err != nil {
+		log.WithError(err).Error("failed to disable two-factor authentication")
+		return err
+	}
+
+	log.Infof("Two-factor authentication disabled for user %s", userID.Hex())
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user.
+func (s *TwoFactorService) RegenerateRecoveryCodes(userID primitive.ObjectID, code string) ([]string, error) {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	user, err := s.getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.TwoFactorEnabled {
+		return nil, fmt.Errorf("two-factor authentication is not enabled")
+	}
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	if _, err := usersColl.UpdateOne(ctx, bson.M{"_id": userID}, bson.M{"$set": bson.M{"recovery_codes": hashes}}); err != nil {
+		log.WithError(err).Error("failed to store recovery codes")
+		return nil, err
+	}
+	return codes, nil
+}
+
+// CompleteLogin finishes a login that was answered with a challenge. Each
+// challenge accepts a limited number of attempts and is used up on success.
+func (s *TwoFactorService) CompleteLogin(challengeToken, code string, info SessionInfo) (*response.UserLoginResponse, error) {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"token_hash": hashToken(challengeToken),
+		"expires_at": bson.M{"$gt": time.Now()},
+		"attempts":   bson.M{"$lt": maxChallengeAttempts},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var challenge models.LoginChallenge
+	if err := collection.FindOneAndUpdate(ctx, filter, bson.M{"$inc": bson.M{"attempts": 1}}, opts).Decode(&challenge); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("login challenge is invalid or has expired, log in again")
+		}
+		return nil, err
+	}
+
+	user, err := s.getUser(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	guard := GetLoginGuard()
+	if err := guard.Check(ctx, user.Email, info.IP); err != nil {
+		return nil, err
+	}
+	if err := s.verifyCode(ctx, user, code); err != nil {
+		guard.Failure(ctx, user.Email, info.IP, user.ID)
+		return nil, err
+	}
+	guard.Success(ctx, user.Email)
+
+	res, err := collection.DeleteOne(ctx, bson.M{"_id": challenge.ID})
+	if err != nil {
+		return nil, err
+	}
+	if res.DeletedCount == 0 {
+		return nil, fmt.Errorf("login challenge has already been used")
+	}
+
+	log.Infof("Two-factor login completed for user %s", user.ID.Hex())
+	return GetUserService().issueLogin(user, info)
+}
+
+func (s *TwoFactorService) createChallenge(ctx context.Context, userID primitive.ObjectID) (string, error) {
+	token, err := generateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	challenge := models.LoginChallenge{
+		ID:        primitive.NewObjectID(),
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		CreatedAt: now,
+		ExpiresAt: now.Add(challengeTTL),
+	}
+	if _, err := database.DB.Collection(s.Collection).InsertOne(ctx, challenge); err != nil {
+		log.WithError(err).Error("failed to insert login challenge")
+		return "", err
+	}
+	return token, nil
+}
+
+// verifyCode accepts an authenticator code or an unused recovery code. Each
+// authenticator code and each recovery code works only once.
+func (s *TwoFactorService) verifyCode(ctx context.Context, user *models.User, code string) error {
+	usersColl := database.DB.Collection(GetUserService().Collection)
+
+	if step, ok := totp.Validate(user.TwoFactorSecret, code, time.Now()); ok {
+		filter := bson.M{"_id": user.ID, "$or": []bson.M{
+			{"two_factor_last_step": bson.M{"$lt": step}},
+			{"two_factor_last_step": bson.M{"$exists": false}},
+		}}
+		res, err := usersColl.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"two_factor_last_step": step}})
+		if err != nil {
+			return err
+		}
+		if res.ModifiedCount == 0 {
+			return fmt.Errorf("authentication code has already been used")
+		}
+		return nil
+	}
+
+	hash := hashToken(normalizeRecoveryCode(code))
+	res, err := usersColl.UpdateOne(ctx,
+		bson.M{"_id": user.ID, "recovery_codes": hash},
+		bson.M{"$pull": bson.M{"recovery_codes": hash}},
+	)
+	if err != nil {
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return fmt.Errorf("invalid authentication code")
+	}
+	log.Infof("Recovery code used by user %s, %d left", user.ID.Hex(), len(user.RecoveryCodes)-1)
+	return nil
+}
+
+func (s *TwoFactorService) getUser(ctx context.Context, userID primitive.ObjectID) (*models.User, error) {
+	var user models.User
+	if err := database.DB.Collection(GetUserService().Collection).FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("user not found")
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+// newRecoveryCodes returns fresh recovery codes in the form xxxxx-xxxxx and
+// the hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		token, err := generateToken(5)
+		if err != nil {
+			return nil, nil, err
+		}
+		codes[i] = token[:5] + "-" + token[5:]
+		hashes[i] = hashToken(normalizeRecoveryCode(codes[i]))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+}
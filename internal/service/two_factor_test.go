@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"managify/internal/totp"
+	"managify/models"
+	"strings"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestVerifyCodeRejectsReplay(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{ID: primitive.NewObjectID(), TwoFactorEnabled: true, TwoFactorSecret: secret}
+	step := totp.Step(time.Now())
+	code, _ := totp.Code(secret, step)
+
+	withMockDB(t, func(mt *mtest.T) {
+		s := GetTwoFactorService()
+
+		mt.AddMockResponses(updateReply(1))
+		if err := s.verifyCode(context.Background(), user, code); err != nil {
+			t.Fatalf("first use: %v", err)
+		}
+		q, u := sentUpdate(t, mt)
+		filter, update := q.String(), u.String()
+		if !strings.Contains(filter, `"two_factor_last_step": {"$lt"`) {
+			t.Errorf("filter %s does not require a newer step", filter)
+		}
+		if !strings.Contains(update, "two_factor_last_step") {
+			t.Errorf("update %s does not record the step", update)
+		}
+
+		// The stored step is no longer lower, so nothing matches.
+		mt.AddMockResponses(updateReply(0))
+		err := s.verifyCode(context.Background(), user, code)
+		if err == nil || !strings.Contains(err.Error(), "already been used") {
+			t.Fatalf("replay: %v, want already used", err)
+		}
+	})
+}
+
+func TestVerifyCodeRecoveryCodeSingleUse(t *testing.T) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &models.User{ID: primitive.NewObjectID(), TwoFactorEnabled: true, TwoFactorSecret: secret, RecoveryCodes: hashes}
+
+	withMockDB(t, func(mt *mtest.T) {
+		s := GetTwoFactorService()
+
+		mt.AddMockResponses(updateReply(1))
+		if err := s.verifyCode(context.Background(), user, strings.ToUpper(codes[0])); err != nil {
+			t.Fatalf("first use: %v", err)
+		}
+		q, u := sentUpdate(t, mt)
+		filter, update := q.String(), u.String()
+		if !strings.Contains(filter, hashes[0]) || !strings.Contains(update, `"$pull": {"recovery_codes": "`+hashes[0]) {
+			t.Errorf("recovery code is not consumed: filter %s, update %s", filter, update)
+		}
+
+		// Once pulled, the hash no longer matches the filter.
+		mt.AddMockResponses(updateReply(0))
+		err := s.verifyCode(context.Background(), user, codes[0])
+		if err == nil || !strings.Contains(err.Error(), "invalid authentication code") {
+			t.Fatalf("reuse: %v, want invalid code", err)
+		}
+	})
+}
+
+func TestRecoveryCodeFormat(t *testing.T) {
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes", len(codes), len(hashes))
+	}
+	seen := map[string]bool{}
+	for i, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Errorf("code %q is not xxxxx-xxxxx", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q is repeated", code)
+		}
+		seen[code] = true
+		if hashToken(normalizeRecoveryCode(" "+strings.ToUpper(code)+" ")) != hashes[i] {
+			t.Errorf("code %q does not match its hash after normalizing", code)
+		}
+	}
+}
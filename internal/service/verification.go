@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/models"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	defaultVerificationHours    = 24
+	defaultVerificationCooldown = 60
+)
+
+// ResendTooSoonError is returned when a verification email was sent less than
+// the resend cooldown ago.
+type ResendTooSoonError struct {
+	RetryAfter time.Duration
+}
+
+func (e *ResendTooSoonError) Error() string {
+	return fmt.Sprintf("verification email was sent recently, try again in %d seconds", int(e.RetryAfter.Seconds()))
+}
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+// verificationTTL reads VERIFICATION_TTL_HOURS, defaulting to 24 hours.
+func verificationTTL() time.Duration {
+	hours, err := strconv.Atoi(os.Getenv("VERIFICATION_TTL_HOURS"))
+	if err != nil || hours <= 0 {
+		hours = defaultVerificationHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// verificationCooldown reads VERIFICATION_RESEND_SECONDS, the minimum time
+// between two verification emails, defaulting to 60 seconds.
+func verificationCooldown() time.Duration {
+	seconds, err := strconv.Atoi(os.Getenv("VERIFICATION_RESEND_SECONDS"))
+	if err != nil || seconds < 0 {
+		seconds = defaultVerificationCooldown
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// newVerification sets a fresh verification token on user and returns the
+// plain token to email. Only its hash is stored.
+func newVerification(user *models.User) (string, error) {
+	token, err := generateToken(32)
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(verificationTTL())
+	user.VerificationToken = hashToken(token)
+	user.VerificationExpiresAt = &expiresAt
+	user.VerificationSentAt = &now
+	return token, nil
+}
+
+// ResendVerification emails a new verification link to an unverified user,
+// replacing the previous one. Resends are limited by verificationCooldown.
+func (s *UserService) ResendVerification(userID primitive.ObjectID) error {
+	collection := database.DB.Collection(s.Collection)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := collection.FindOne(ctx, bson.M{"_id": userID}).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return fmt.Errorf("user not found")
+		}
+		return err
+	}
+	if user.IsVerified {
+		return fmt.Errorf("email is already verified")
+	}
+
+	cooldown := verificationCooldown()
+	previous := user.VerificationSentAt
+	token, err := newVerification(&user)
+	if err != nil {
+		return err
+	}
+
+	// The sent_at condition makes concurrent resends race for a single slot.
+	filter := bson.M{"_id": userID, "isverified": false}
+	if previous != nil {
+		if wait := previous.Add(cooldown).Sub(time.Now()); wait > 0 {
+			return &ResendTooSoonError{RetryAfter: wait.Round(time.Second)}
+		}
+		filter["verification_sent_at"] = *previous
+	}
+	update := bson.M{"$set": bson.M{
+		"verificationtoken":       user.VerificationToken,
+		"verification_expires_at": user.VerificationExpiresAt,
+		"verification_sent_at":    user.VerificationSentAt,
+	}}
+	res, err := collection.UpdateOne(ctx, filter, update)
+	if err != nil {
+		log.WithError(err).Error("failed to store verification token")
+		return err
+	}
+	if res.ModifiedCount == 0 {
+		return &ResendTooSoonError{RetryAfter: cooldown}
+	}
+
+	if err := sendVerificationEmail(ctx, &user, token); err != nil {
+		log.WithError(err).Errorf("failed to queue verification email to %s", user.Email)
+		return err
+	}
+
+	log.Infof("Verification email resent to user %s", userID.Hex())
+	return nil
+}
+
+// IsVerified is used by middleware.AuthMiddleware for tokens issued before
+// the user verified their email.
+func (s *UserService) IsVerified(userID primitive.ObjectID) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var user models.User
+	opts := options.FindOne().SetProjection(bson.M{"isverified": 1})
+	if err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": userID}, opts).Decode(&user); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return false, nil
+		}
+		return false, err
+	}
+	return user.IsVerified, nil
+}
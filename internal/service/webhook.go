@@ -0,0 +1,600 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"managify/database"
+	"managify/dto/request"
+	"managify/internal/realtime"
+	"managify/internal/webhook"
+	"managify/models"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	maxWebhooksPerProject     = 10
+	defaultWebhookMaxAttempts = 6
+	// webhookRetryBase is the delay before the first retry; it doubles with
+	// every further attempt up to webhookRetryMax.
+	webhookRetryBase = 30 * time.Second
+	webhookRetryMax  = time.Hour
+	// webhookTimeout bounds one request; a claimed delivery whose lock has
+	// passed is picked up again, e.g. after a crash.
+	webhookTimeout      = 10 * time.Second
+	webhookLockTTL      = time.Minute
+	webhookBatchSize    = 50
+	webhookHistoryLimit = 20
+	webhookResponseMax  = 512
+)
+
+// WebhookService manages the webhooks of projects and delivers their events
+// from the Deliveries collection with retries.
+type WebhookService struct {
+	Collection string
+	Deliveries string
+	Client     *http.Client
+
+	wake chan struct{}
+}
+
+var webhookService *WebhookService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetWebhookService() *WebhookService {
+	if webhookService == nil {
+		webhookService = &WebhookService{
+			Collection: "webhooks",
+			Deliveries: "webhook_deliveries",
+			Client:     newWebhookClient(os.Getenv("WEBHOOK_ALLOW_PRIVATE_NETWORKS") == "true"),
+			wake:       make(chan struct{}, 1),
+		}
+	}
+	return webhookService
+}
+
+// newWebhookClient returns the HTTP client used for deliveries. Unless
+// allowPrivate is set it refuses to connect to loopback, private and
+// link-local addresses, so webhooks cannot reach internal services. The check
+// runs on the resolved address and does not follow redirects.
+func newWebhookClient(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			if allowPrivate {
+				return nil
+			}
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+				ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsMulticast() {
+				return fmt.Errorf("webhook address %s is not allowed", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout: webhookTimeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: 5 * time.Second,
+			MaxIdleConnsPerHost: 2,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// webhookMaxAttempts reads WEBHOOK_MAX_ATTEMPTS, defaulting to 6.
+func webhookMaxAttempts() int {
+	return envInt("WEBHOOK_MAX_ATTEMPTS", defaultWebhookMaxAttempts)
+}
+
+func validateWebhookURL(raw string) (string, error) {
+	raw = strings.TrimSpace(raw)
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("url must be an absolute http or https URL")
+	}
+	if u.User != nil {
+		return "", fmt.Errorf("url must not contain credentials")
+	}
+	return raw, nil
+}
+
+func validateWebhookEvents(events []string) ([]string, error) {
+	out := []string{}
+	for _, e := range events {
+		if !slices.Contains(models.WebhookEvents, e) {
+			return nil, fmt.Errorf("invalid webhook event: %s", e)
+		}
+		if !slices.Contains(out, e) {
+			out = append(out, e)
+		}
+	}
+	return out, nil
+}
+
+// CreateWebhook adds a webhook to the project. The secret is returned so a
+// generated one can be shown once; it is never listed again.
+func (s *WebhookService) CreateWebhook(projectID, userID primitive.ObjectID, req *request.WebhookCreateRequest) (*models.Webhook, string, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, "", err
+	}
+	target, err := validateWebhookURL(req.URL)
+	if err != nil {
+		return nil, "", err
+	}
+	events, err := validateWebhookEvents(req.Events)
+	if err != nil {
+		return nil, "", err
+	}
+	secret := req.Secret
+	if secret == "" {
+		token, err := generateToken(24)
+		if err != nil {
+			return nil, "", err
+		}
+		secret = "whsec_" + token
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	collection := database.DB.Collection(s.Collection)
+	count, err := collection.CountDocuments(ctx, bson.M{"project_id": projectID})
+	if err != nil {
+		return nil, "", err
+	}
+	if count >= maxWebhooksPerProject {
+		return nil, "", fmt.Errorf("a project can have at most %d webhooks", maxWebhooksPerProject)
+	}
+
+	now := time.Now()
+	hook := models.Webhook{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID,
+		URL:       target,
+		Secret:    secret,
+		Events:    events,
+		Active:    true,
+		CreatedBy: userID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if _, err := collection.InsertOne(ctx, hook); err != nil {
+		log.WithError(err).Error("failed to insert webhook")
+		return nil, "", err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Webhook has been added -> " + hook.URL,
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, "", err
+	}
+	return &hook, secret, nil
+}
+
+// GetWebhooks lists the webhooks of a project.
+func (s *WebhookService) GetWebhooks(projectID primitive.ObjectID) ([]*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := database.DB.Collection(s.Collection).Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	hooks := []*models.Webhook{}
+	if err := cursor.All(ctx, &hooks); err != nil {
+		return nil, err
+	}
+	return hooks, nil
+}
+
+func (s *WebhookService) getWebhook(ctx context.Context, projectID, webhookID primitive.ObjectID) (*models.Webhook, error) {
+	var hook models.Webhook
+	err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": webhookID, "project_id": projectID}).Decode(&hook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// UpdateWebhook changes the URL, secret, event filter or active flag.
+func (s *WebhookService) UpdateWebhook(projectID, webhookID, userID primitive.ObjectID, req *request.WebhookUpdateRequest) (*models.Webhook, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	set := bson.M{"updated_at": now}
+	if req.URL != nil {
+		target, err := validateWebhookURL(*req.URL)
+		if err != nil {
+			return nil, err
+		}
+		set["url"] = target
+	}
+	if req.Secret != nil {
+		if *req.Secret == "" {
+			return nil, fmt.Errorf("secret must not be empty")
+		}
+		set["secret"] = *req.Secret
+	}
+	if req.Events != nil {
+		events, err := validateWebhookEvents(*req.Events)
+		if err != nil {
+			return nil, err
+		}
+		set["events"] = events
+	}
+	if req.Active != nil {
+		set["active"] = *req.Active
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var hook models.Webhook
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	err := database.DB.Collection(s.Collection).FindOneAndUpdate(ctx,
+		bson.M{"_id": webhookID, "project_id": projectID}, bson.M{"$set": set}, opts,
+	).Decode(&hook)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Webhook has been updated -> " + hook.URL,
+		Timestamp: now,
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+	return &hook, nil
+}
+
+// DeleteWebhook removes a webhook together with its deliveries.
+func (s *WebhookService) DeleteWebhook(projectID, webhookID, userID primitive.ObjectID) error {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	hook, err := s.getWebhook(ctx, projectID, webhookID)
+	if err != nil {
+		return err
+	}
+	if _, err := database.DB.Collection(s.Collection).DeleteOne(ctx, bson.M{"_id": hook.ID}); err != nil {
+		log.WithError(err).Error("failed to delete webhook")
+		return err
+	}
+	if _, err := database.DB.Collection(s.Deliveries).DeleteMany(ctx, bson.M{"webhook_id": hook.ID}); err != nil {
+		log.WithError(err).Error("failed to delete webhook deliveries")
+		return err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    userID.Hex(),
+		Message:   "Webhook has been removed -> " + hook.URL,
+		Timestamp: time.Now(),
+	}
+	return GetLogService().CreateLog(&projectLog)
+}
+
+// GetDeliveries lists the deliveries of a webhook, newest first.
+func (s *WebhookService) GetDeliveries(projectID, webhookID primitive.ObjectID, limit int64) ([]*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := s.getWebhook(ctx, projectID, webhookID); err != nil {
+		return nil, err
+	}
+
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: -1}}).SetLimit(limit)
+	cursor, err := database.DB.Collection(s.Deliveries).Find(ctx, bson.M{"webhook_id": webhookID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	deliveries := []*models.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// Redeliver queues a finished delivery again with a fresh set of attempts.
+// The payload is unchanged; the attempt history is kept.
+func (s *WebhookService) Redeliver(projectID, webhookID, deliveryID primitive.ObjectID) (*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"_id":        deliveryID,
+		"webhook_id": webhookID,
+		"project_id": projectID,
+		"status":     bson.M{"$in": []models.DeliveryStatus{models.DeliveryDelivered, models.DeliveryFailed}},
+	}
+	update := bson.M{
+		"$set":   bson.M{"status": models.DeliveryPending, "attempts": 0, "next_attempt_at": time.Now()},
+		"$unset": bson.M{"delivered_at": ""},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	if err := database.DB.Collection(s.Deliveries).FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, fmt.Errorf("delivery not found or still in progress")
+		}
+		return nil, err
+	}
+
+	s.signal()
+	return &delivery, nil
+}
+
+func (s *WebhookService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Dispatch queues a delivery of the event for every active webhook of the
+// project subscribed to it. It returns immediately; the payload is encoded
+// before returning so later changes to data are not sent.
+func (s *WebhookService) Dispatch(projectID, actorID primitive.ObjectID, event string, data any) {
+	now := time.Now()
+	payload, err := json.Marshal(map[string]any{
+		"id":         primitive.NewObjectID(),
+		"event":      event,
+		"project_id": projectID,
+		"actor_id":   actorID,
+		"created_at": now,
+		"data":       data,
+	})
+	if err != nil {
+		log.WithError(err).Errorf("failed to encode webhook event %s", event)
+		return
+	}
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		filter := bson.M{"project_id": projectID, "active": true}
+		cursor, err := database.DB.Collection(s.Collection).Find(ctx, filter)
+		if err != nil {
+			log.WithError(err).Error("failed to load webhooks")
+			return
+		}
+		var hooks []models.Webhook
+		if err := cursor.All(ctx, &hooks); err != nil {
+			log.WithError(err).Error("failed to load webhooks")
+			return
+		}
+
+		var deliveries []any
+		for _, hook := range hooks {
+			if !hook.Subscribed(event) {
+				continue
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				ID:            primitive.NewObjectID(),
+				WebhookID:     hook.ID,
+				ProjectID:     projectID,
+				Event:         event,
+				Payload:       string(payload),
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			})
+		}
+		if len(deliveries) == 0 {
+			return
+		}
+		if _, err := database.DB.Collection(s.Deliveries).InsertMany(ctx, deliveries); err != nil {
+			log.WithError(err).Errorf("failed to queue webhook deliveries for %s", event)
+			return
+		}
+		s.signal()
+	}()
+}
+
+// DeliverDue sends the deliveries whose next attempt is due and returns how
+// many were handled.
+func (s *WebhookService) DeliverDue() (int, error) {
+	handled := 0
+	for handled < webhookBatchSize {
+		delivery, err := s.claim()
+		if err != nil {
+			return handled, err
+		}
+		if delivery == nil {
+			break
+		}
+		s.deliver(delivery)
+		handled++
+	}
+	return handled, nil
+}
+
+// claim locks the next due delivery so concurrent workers do not send it
+// twice.
+func (s *WebhookService) claim() (*models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	now := time.Now()
+	filter := bson.M{"$or": []bson.M{
+		{"status": models.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+		{"status": models.DeliverySending, "locked_until": bson.M{"$lt": now}},
+	}}
+	update := bson.M{
+		"$set": bson.M{"status": models.DeliverySending, "locked_until": now.Add(webhookLockTTL)},
+		"$inc": bson.M{"attempts": 1},
+	}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var delivery models.WebhookDelivery
+	if err := database.DB.Collection(s.Deliveries).FindOneAndUpdate(ctx, filter, update, opts).Decode(&delivery); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// deliver sends a claimed delivery and records the attempt. Failed attempts
+// are retried with exponential backoff until webhookMaxAttempts is reached.
+// Deliveries of deleted or deactivated webhooks fail without a request.
+func (s *WebhookService) deliver(delivery *models.WebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout+5*time.Second)
+	defer cancel()
+
+	var hook models.Webhook
+	var attempt models.WebhookAttempt
+	err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"_id": delivery.WebhookID, "active": true}).Decode(&hook)
+	switch {
+	case err == mongo.ErrNoDocuments:
+		attempt = models.WebhookAttempt{At: time.Now(), Error: "webhook is deleted or inactive"}
+		delivery.Attempts = webhookMaxAttempts()
+	case err != nil:
+		attempt = models.WebhookAttempt{At: time.Now(), Error: err.Error()}
+	default:
+		attempt = s.send(ctx, &hook, delivery)
+	}
+
+	now := time.Now()
+	succeeded := attempt.Error == "" && attempt.StatusCode >= 200 && attempt.StatusCode < 300
+	update := bson.M{
+		"$push":  bson.M{"history": bson.M{"$each": []models.WebhookAttempt{attempt}, "$slice": -webhookHistoryLimit}},
+		"$unset": bson.M{"locked_until": ""},
+	}
+	switch {
+	case succeeded:
+		update["$set"] = bson.M{"status": models.DeliveryDelivered, "delivered_at": now}
+		log.Infof("Webhook %s delivered %s", hook.ID.Hex(), delivery.Event)
+	case delivery.Attempts >= webhookMaxAttempts():
+		update["$set"] = bson.M{"status": models.DeliveryFailed}
+		log.Errorf("Webhook %s failed to deliver %s after %d attempts", delivery.WebhookID.Hex(), delivery.Event, delivery.Attempts)
+	default:
+		delay := webhookRetryBase << (delivery.Attempts - 1)
+		if delay > webhookRetryMax || delay <= 0 {
+			delay = webhookRetryMax
+		}
+		update["$set"] = bson.M{"status": models.DeliveryPending, "next_attempt_at": now.Add(delay)}
+		log.Warnf("Webhook %s failed to deliver %s, retrying in %s", delivery.WebhookID.Hex(), delivery.Event, delay)
+	}
+
+	if _, err := database.DB.Collection(s.Deliveries).UpdateOne(ctx, bson.M{"_id": delivery.ID}, update); err != nil {
+		log.WithError(err).Error("failed to record webhook delivery")
+	}
+}
+
+// send posts the signed payload to the webhook URL.
+func (s *WebhookService) send(ctx context.Context, hook *models.Webhook, delivery *models.WebhookDelivery) models.WebhookAttempt {
+	start := time.Now()
+	attempt := models.WebhookAttempt{At: start}
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Managify-Webhook/1.0")
+	req.Header.Set(webhook.HeaderEvent, delivery.Event)
+	req.Header.Set(webhook.HeaderDelivery, delivery.ID.Hex())
+	req.Header.Set(webhook.HeaderTimestamp, fmt.Sprint(start.Unix()))
+	req.Header.Set(webhook.HeaderSignature, webhook.Sign(hook.Secret, start.Unix(), body))
+
+	resp, err := s.Client.Do(req)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseMax))
+	attempt.StatusCode = resp.StatusCode
+	attempt.Response = string(snippet)
+	return attempt
+}
+
+// StartWebhookDispatcher delivers queued webhook events every interval and
+// whenever one is queued.
+func StartWebhookDispatcher(interval time.Duration) {
+	s := GetWebhookService()
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+			case <-s.wake:
+			}
+			if _, err := s.DeliverDue(); err != nil {
+				log.WithError(err).Error("Webhook delivery failed")
+			}
+		}
+	}()
+}
+
+// publishEvent tells the project's realtime subscribers and webhooks about a
+// board change.
+func publishEvent(projectID, actorID primitive.ObjectID, event string, data any) {
+	realtime.Publish(projectID, actorID, event, data)
+	GetWebhookService().Dispatch(projectID, actorID, event, data)
+}
@@ -0,0 +1,318 @@
+package service
+
+import (
+	"io"
+	"managify/dto/request"
+	"managify/internal/webhook"
+	"managify/models"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+const testWebhookSecret = "whsec_test"
+
+// webhookReceiver is a local endpoint that verifies every delivery and
+// answers with status.
+type webhookReceiver struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	status   int
+	received []string
+}
+
+func newWebhookReceiver(t *testing.T, status int) *webhookReceiver {
+	r := &webhookReceiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		if !webhook.Verify(testWebhookSecret, req.Header.Get(webhook.HeaderTimestamp), req.Header.Get(webhook.HeaderSignature), body, time.Minute) {
+			t.Errorf("delivery %s has an invalid signature", req.Header.Get(webhook.HeaderDelivery))
+		}
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.received = append(r.received, req.Header.Get(webhook.HeaderEvent)+" "+string(body))
+		w.WriteHeader(r.status)
+		io.WriteString(w, "ok")
+	}))
+	t.Cleanup(r.Close)
+	return r
+}
+
+func (r *webhookReceiver) requests() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]string(nil), r.received...)
+}
+
+func newTestWebhookService(allowPrivate bool) *WebhookService {
+	return &WebhookService{
+		Collection: "webhooks",
+		Deliveries: "webhook_deliveries",
+		Client:     newWebhookClient(allowPrivate),
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+func testDelivery(hook *models.Webhook, attempts int) *models.WebhookDelivery {
+	return &models.WebhookDelivery{
+		ID:        primitive.NewObjectID(),
+		WebhookID: hook.ID,
+		ProjectID: hook.ProjectID,
+		Event:     models.WebhookIssueCreated,
+		Payload:   `{"event":"issue.created"}`,
+		Status:    models.DeliverySending,
+		Attempts:  attempts,
+	}
+}
+
+func testWebhook(url string) *models.Webhook {
+	return &models.Webhook{
+		ID:        primitive.NewObjectID(),
+		ProjectID: primitive.NewObjectID(),
+		URL:       url,
+		Secret:    testWebhookSecret,
+		Active:    true,
+	}
+}
+
+// deliverOnce runs deliver against the mocked webhook lookup and returns the
+// recorded update.
+func deliverOnce(t *testing.T, mt *mtest.T, s *WebhookService, hook *models.Webhook, delivery *models.WebhookDelivery) bson.Raw {
+	t.Helper()
+	mt.ClearEvents()
+	mt.AddMockResponses(docsReply(t, hook), updateReply(1))
+	s.deliver(delivery)
+
+	mt.GetStartedEvent() // webhook lookup
+	_, u := sentUpdate(t, mt)
+	return u
+}
+
+func TestWebhookDeliverySucceeds(t *testing.T) {
+	receiver := newWebhookReceiver(t, http.StatusNoContent)
+	s := newTestWebhookService(true)
+	hook := testWebhook(receiver.URL)
+
+	withMockDB(t, func(mt *mtest.T) {
+		u := deliverOnce(t, mt, s, hook, testDelivery(hook, 1))
+
+		if u.Lookup("$set", "status").StringValue() != string(models.DeliveryDelivered) {
+			t.Errorf("update %s does not mark the delivery delivered", u)
+		}
+		if code := u.Lookup("$push", "history", "$each", "0", "status_code").AsInt64(); code != http.StatusNoContent {
+			t.Errorf("recorded status %d", code)
+		}
+	})
+	if got := receiver.requests(); len(got) != 1 || got[0] != `issue.created {"event":"issue.created"}` {
+		t.Errorf("receiver got %q", got)
+	}
+}
+
+func TestWebhookDeliveryBacksOffOnServerError(t *testing.T) {
+	receiver := newWebhookReceiver(t, http.StatusBadGateway)
+	s := newTestWebhookService(true)
+	hook := testWebhook(receiver.URL)
+
+	withMockDB(t, func(mt *mtest.T) {
+		for _, tt := range []struct {
+			attempts int
+			delay    time.Duration
+		}{
+			{1, 30 * time.Second},
+			{2, time.Minute},
+			{3, 2 * time.Minute},
+			{4, 4 * time.Minute},
+		} {
+			// BSON dates have millisecond precision.
+			before := time.Now().Truncate(time.Millisecond)
+			u := deliverOnce(t, mt, s, hook, testDelivery(hook, tt.attempts))
+
+			set := u.Lookup("$set").Document()
+			if set.Lookup("status").StringValue() != string(models.DeliveryPending) {
+				t.Errorf("attempt %d: update %s does not retry", tt.attempts, u)
+			}
+			if got := set.Lookup("next_attempt_at").Time().Sub(before); got < tt.delay || got > tt.delay+5*time.Second {
+				t.Errorf("attempt %d: retried after %s, want %s", tt.attempts, got, tt.delay)
+			}
+			if code := u.Lookup("$push", "history", "$each", "0", "status_code").AsInt64(); code != http.StatusBadGateway {
+				t.Errorf("attempt %d: recorded status %d", tt.attempts, code)
+			}
+		}
+	})
+	if n := len(receiver.requests()); n != 4 {
+		t.Errorf("receiver got %d requests, want 4", n)
+	}
+}
+
+func TestWebhookDeliveryBackoffIsCapped(t *testing.T) {
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "20")
+	receiver := newWebhookReceiver(t, http.StatusInternalServerError)
+	s := newTestWebhookService(true)
+	hook := testWebhook(receiver.URL)
+
+	withMockDB(t, func(mt *mtest.T) {
+		before := time.Now().Truncate(time.Millisecond)
+		u := deliverOnce(t, mt, s, hook, testDelivery(hook, 12))
+
+		if got := u.Lookup("$set", "next_attempt_at").Time().Sub(before); got < webhookRetryMax || got > webhookRetryMax+5*time.Second {
+			t.Errorf("retried after %s, want %s", got, webhookRetryMax)
+		}
+	})
+}
+
+func TestWebhookDeliveryFailsAfterMaxAttempts(t *testing.T) {
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "3")
+	receiver := newWebhookReceiver(t, http.StatusServiceUnavailable)
+	s := newTestWebhookService(true)
+	hook := testWebhook(receiver.URL)
+
+	withMockDB(t, func(mt *mtest.T) {
+		if u := deliverOnce(t, mt, s, hook, testDelivery(hook, 2)); u.Lookup("$set", "status").StringValue() != string(models.DeliveryPending) {
+			t.Errorf("attempt 2: update %s, want a retry", u)
+		}
+		if u := deliverOnce(t, mt, s, hook, testDelivery(hook, 3)); u.Lookup("$set", "status").StringValue() != string(models.DeliveryFailed) {
+			t.Errorf("attempt 3: update %s, want failed", u)
+		}
+	})
+}
+
+func TestRedeliverResetsAttempts(t *testing.T) {
+	s := newTestWebhookService(false)
+	hook := testWebhook("https://hooks.example.com")
+	delivery := testDelivery(hook, 6)
+	delivery.Status = models.DeliveryFailed
+
+	withMockDB(t, func(mt *mtest.T) {
+		queued := *delivery
+		queued.Status, queued.Attempts = models.DeliveryPending, 0
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: docsValue(t, queued)}))
+
+		got, err := s.Redeliver(hook.ProjectID, hook.ID, delivery.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Status != models.DeliveryPending || got.Attempts != 0 {
+			t.Errorf("redelivered %+v", got)
+		}
+
+		cmd := mt.GetStartedEvent().Command
+		filter := cmd.Lookup("query").Document()
+		update := cmd.Lookup("update").Document()
+		if !strings.Contains(filter.Lookup("status").String(), string(models.DeliveryFailed)) ||
+			strings.Contains(filter.Lookup("status").String(), string(models.DeliverySending)) {
+			t.Errorf("filter %s may redeliver deliveries in progress", filter)
+		}
+		if update.Lookup("$set", "attempts").AsInt64() != 0 || update.Lookup("$set", "status").StringValue() != string(models.DeliveryPending) {
+			t.Errorf("update %s does not reset the delivery", update)
+		}
+		select {
+		case <-s.wake:
+		default:
+			t.Error("dispatcher was not woken up")
+		}
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+		if _, err := s.Redeliver(hook.ProjectID, hook.ID, delivery.ID); err == nil {
+			t.Error("redelivered a delivery that is still in progress")
+		}
+	})
+}
+
+func TestWebhookClientBlocksPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	defer server.Close()
+	if !strings.HasPrefix(server.URL, "http://127.0.0.1:") {
+		t.Fatalf("server listens on %s", server.URL)
+	}
+
+	res, err := newWebhookClient(false).Get(server.URL)
+	if err == nil {
+		res.Body.Close()
+		t.Fatal("webhook client connected to 127.0.0.1")
+	}
+	if !strings.Contains(err.Error(), "not allowed") {
+		t.Errorf("error = %v", err)
+	}
+
+	res, err = newWebhookClient(true).Get(server.URL)
+	if err != nil {
+		t.Fatalf("allowPrivate client: %v", err)
+	}
+	res.Body.Close()
+}
+
+func TestWebhookSendRecordsBlockedAddress(t *testing.T) {
+	receiver := newWebhookReceiver(t, http.StatusOK)
+	s := newTestWebhookService(false)
+	hook := testWebhook(receiver.URL)
+
+	withMockDB(t, func(mt *mtest.T) {
+		u := deliverOnce(t, mt, s, hook, testDelivery(hook, 1))
+
+		if msg := u.Lookup("$push", "history", "$each", "0", "error").StringValue(); !strings.Contains(msg, "not allowed") {
+			t.Errorf("recorded error %q", msg)
+		}
+		if u.Lookup("$set", "status").StringValue() != string(models.DeliveryPending) {
+			t.Errorf("update %s, want a retry", u)
+		}
+	})
+	if n := len(receiver.requests()); n != 0 {
+		t.Errorf("receiver got %d requests", n)
+	}
+}
+
+func TestUpdateWebhookLogsChange(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		project := models.Project{ID: primitive.NewObjectID()}
+		hook := models.Webhook{ID: primitive.NewObjectID(), ProjectID: project.ID, URL: "https://hooks.example.com/a"}
+		userID := primitive.NewObjectID()
+
+		mt.AddMockResponses(
+			docsReply(t, project),
+			mtest.CreateSuccessResponse(bson.E{Key: "value", Value: docsValue(t, hook)}),
+			mtest.CreateSuccessResponse(),
+		)
+
+		active := false
+		if _, err := GetWebhookService().UpdateWebhook(project.ID, hook.ID, userID, &request.WebhookUpdateRequest{Active: &active}); err != nil {
+			t.Fatal(err)
+		}
+
+		var logged bson.Raw
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "insert" {
+				logged = e.Command.Lookup("documents", "0").Document()
+			}
+		}
+		if logged == nil || logged.Lookup("user_id").StringValue() != userID.Hex() {
+			t.Errorf("project log = %v", logged)
+		}
+	})
+}
+
+func TestWebhookChangesRefusedInArchivedProject(t *testing.T) {
+	withMockDB(t, func(mt *mtest.T) {
+		project := models.Project{ID: primitive.NewObjectID(), Status: models.ProjectArchived}
+		mt.AddMockResponses(docsReply(t, project), docsReply(t, project))
+
+		active := true
+		if _, err := GetWebhookService().UpdateWebhook(project.ID, primitive.NewObjectID(), primitive.NewObjectID(), &request.WebhookUpdateRequest{Active: &active}); err == nil {
+			t.Error("updated a webhook of an archived project")
+		}
+		if err := GetWebhookService().DeleteWebhook(project.ID, primitive.NewObjectID(), primitive.NewObjectID()); err == nil {
+			t.Error("deleted a webhook of an archived project")
+		}
+		if got := sentCommands(mt); !slices.Equal(got, []string{"find", "find"}) {
+			t.Errorf("commands = %v, want only the project lookups", got)
+		}
+	})
+}
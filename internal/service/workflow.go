@@ -0,0 +1,274 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"managify/database"
+	"managify/dto/request"
+	"managify/models"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type WorkflowService struct {
+	Collection string
+}
+
+var workflowService *WorkflowService
+
+func init() {
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.DebugLevel)
+}
+
+func GetWorkflowService() *WorkflowService {
+	if workflowService == nil {
+		workflowService = &WorkflowService{Collection: "workflows"}
+	}
+	return workflowService
+}
+
+// GetWorkflow returns the workflow of a project. Projects that never defined
+// one get an empty workflow, which allows every move.
+func (s *WorkflowService) GetWorkflow(projectID primitive.ObjectID) (*models.Workflow, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	return s.getWorkflow(ctx, projectID)
+}
+
+// SetWorkflow replaces the transition rules of a project.
+func (s *WorkflowService) SetWorkflow(actorID, projectID primitive.ObjectID, req *request.WorkflowRequest) (*models.Workflow, error) {
+	if err := GetProjectService().EnsureWritable(projectID); err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	transitions, err := s.parseTransitions(ctx, projectID, req.Transitions)
+	if err != nil {
+		return nil, err
+	}
+
+	update := bson.M{
+		"$set": bson.M{
+			"transitions": transitions,
+			"updated_by":  actorID,
+			"updated_at":  time.Now(),
+		},
+		"$setOnInsert": bson.M{"_id": primitive.NewObjectID()},
+	}
+	opts := options.FindOneAndUpdate().SetUpsert(true).SetReturnDocument(options.After)
+
+	var workflow models.Workflow
+	collection := database.DB.Collection(s.Collection)
+	if err := collection.FindOneAndUpdate(ctx, bson.M{"project_id": projectID}, update, opts).Decode(&workflow); err != nil {
+		log.WithError(err).Error("failed to save workflow")
+		return nil, err
+	}
+
+	projectLog := models.ProjectLog{
+		ID:        primitive.NewObjectID(),
+		ProjectID: projectID.Hex(),
+		UserID:    actorID.Hex(),
+		Message:   fmt.Sprintf("Workflow Has Been Updated -> %d transitions", len(transitions)),
+		Timestamp: time.Now(),
+	}
+	if err := GetLogService().CreateLog(&projectLog); err != nil {
+		return nil, err
+	}
+
+	return &workflow, nil
+}
+
+// CheckTransition returns an error describing why userID may not move issue
+// into the status to, or nil if the project's workflow allows it. resolution
+// is the note supplied with the move.
+func (s *WorkflowService) CheckTransition(ctx context.Context, issue *models.Issue, to *models.Status, userID primitive.ObjectID, resolution string) error {
+	workflow, err := s.getWorkflow(ctx, issue.ProjectID)
+	if err != nil {
+		return err
+	}
+	if len(workflow.Transitions) == 0 || issue.StatusID == to.ID {
+		return nil
+	}
+
+	var candidates []models.WorkflowTransition
+	for _, t := range workflow.Transitions {
+		if t.ToStatusID == to.ID && (t.FromStatusID.IsZero() || t.FromStatusID == issue.StatusID) {
+			candidates = append(candidates, t)
+		}
+	}
+	from := GetIssueService().statusName(issue.StatusID)
+	if len(candidates) == 0 {
+		return fmt.Errorf("moving issues from %s to %s is not allowed by the project workflow", from, to.Name)
+	}
+
+	role, bypass, err := s.callerRole(issue.ProjectID, userID)
+	if err != nil {
+		return err
+	}
+
+	var roleErr, fieldErr error
+	for _, t := range candidates {
+		if !bypass && len(t.AllowedRoles) > 0 && !slices.Contains(t.AllowedRoles, string(role)) {
+			roleErr = fmt.Errorf("role %s may not move issues from %s to %s", role, from, to.Name)
+			continue
+		}
+		if missing := missingFields(issue, t.RequiredFields, resolution); len(missing) > 0 {
+			fieldErr = fmt.Errorf("moving to %s requires: %s", to.Name, strings.Join(missing, ", "))
+			continue
+		}
+		return nil
+	}
+
+	if fieldErr != nil {
+		return fieldErr
+	}
+	return roleErr
+}
+
+// ReferencesStatus reports whether a transition of the project's workflow
+// starts or ends in statusID.
+func (s *WorkflowService) ReferencesStatus(ctx context.Context, projectID, statusID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"project_id": projectID,
+		"$or": []bson.M{
+			{"transitions.from_status_id": statusID},
+			{"transitions.to_status_id": statusID},
+		},
+	}
+	count, err := database.DB.Collection(s.Collection).CountDocuments(ctx, filter)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RenameRole updates the role restrictions of a project after a custom role
+// was renamed.
+func (s *WorkflowService) RenameRole(ctx context.Context, projectID primitive.ObjectID, oldName, newName string) error {
+	update := bson.M{"$set": bson.M{"transitions.$[t].allowed_roles.$[r]": newName}}
+	opts := options.Update().SetArrayFilters(options.ArrayFilters{Filters: []interface{}{
+		bson.M{"t.allowed_roles": oldName},
+		bson.M{"r": oldName},
+	}})
+	_, err := database.DB.Collection(s.Collection).UpdateOne(ctx, bson.M{"project_id": projectID}, update, opts)
+	return err
+}
+
+func (s *WorkflowService) getWorkflow(ctx context.Context, projectID primitive.ObjectID) (*models.Workflow, error) {
+	var workflow models.Workflow
+	err := database.DB.Collection(s.Collection).FindOne(ctx, bson.M{"project_id": projectID}).Decode(&workflow)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return &models.Workflow{ProjectID: projectID, Transitions: []models.WorkflowTransition{}}, nil
+		}
+		return nil, err
+	}
+	return &workflow, nil
+}
+
+// callerRole resolves the role used for role restrictions. The project owner
+// and admins acting outside their own projects are not restricted.
+func (s *WorkflowService) callerRole(projectID, userID primitive.ObjectID) (models.ProjectRole, bool, error) {
+	role, err := GetRoleService().GetUserRole(userID, projectID)
+	if err != nil {
+		if !IsNotProjectMember(err) {
+			return "", false, err
+		}
+		user, uerr := GetUserService().GetUserById(userID.Hex())
+		if uerr != nil || !user.IsAdmin {
+			return "", false, err
+		}
+		return "", true, nil
+	}
+	return role, role == models.RoleOwner, nil
+}
+
+func (s *WorkflowService) parseTransitions(ctx context.Context, projectID primitive.ObjectID, reqs []request.WorkflowTransitionRequest) ([]models.WorkflowTransition, error) {
+	statuses, err := GetBoardService().projectStatuses(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	inProject := func(id primitive.ObjectID) bool {
+		return slices.ContainsFunc(statuses, func(st *models.Status) bool { return st.ID == id })
+	}
+
+	definitions, err := GetRoleDefinitionService().GetDefinitions(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	transitions := make([]models.WorkflowTransition, 0, len(reqs))
+	for _, r := range reqs {
+		var t models.WorkflowTransition
+
+		if r.FromStatusID != "" {
+			id, err := primitive.ObjectIDFromHex(r.FromStatusID)
+			if err != nil || !inProject(id) {
+				return nil, fmt.Errorf("from_status_id %s is not a status of the project", r.FromStatusID)
+			}
+			t.FromStatusID = id
+		}
+		id, err := primitive.ObjectIDFromHex(r.ToStatusID)
+		if err != nil || !inProject(id) {
+			return nil, fmt.Errorf("to_status_id %s is not a status of the project", r.ToStatusID)
+		}
+		t.ToStatusID = id
+
+		for _, f := range r.RequiredFields {
+			if !slices.Contains(models.TransitionFields, f) {
+				return nil, fmt.Errorf("unknown required field %s, expected one of %s", f, strings.Join(models.TransitionFields, ", "))
+			}
+		}
+		t.RequiredFields = r.RequiredFields
+
+		for _, role := range r.AllowedRoles {
+			custom := slices.ContainsFunc(definitions, func(d *models.RoleDefinition) bool { return d.Name == role })
+			if !IsBuiltinRole(role) && !custom {
+				return nil, fmt.Errorf("unknown role %s", role)
+			}
+			if IsBuiltinRole(role) {
+				role = strings.ToUpper(role)
+			}
+			t.AllowedRoles = append(t.AllowedRoles, role)
+		}
+
+		transitions = append(transitions, t)
+	}
+
+	return transitions, nil
+}
+
+func missingFields(issue *models.Issue, required []string, resolution string) []string {
+	var missing []string
+	for _, f := range required {
+		var present bool
+		switch f {
+		case models.FieldResolution:
+			present = strings.TrimSpace(resolution) != ""
+		case models.FieldAssignee:
+			present = !issue.AssigneeID.IsZero()
+		case models.FieldDueDate:
+			present = issue.DueDate != ""
+		case models.FieldDescription:
+			present = strings.TrimSpace(issue.Description) != ""
+		}
+		if !present {
+			missing = append(missing, f)
+		}
+	}
+	return missing
+}
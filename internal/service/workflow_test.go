@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"managify/models"
+	"slices"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestDeleteStatusKeepsWorkflowEnforced(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	userID := primitive.NewObjectID()
+	todo := primitive.NewObjectID()
+	done := primitive.NewObjectID()
+	later := primitive.NewObjectID()
+
+	// The only transition ends in done, so dropping it would empty the workflow.
+	workflow := models.Workflow{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   projectID,
+		Transitions: []models.WorkflowTransition{{FromStatusID: todo, ToStatusID: done}},
+	}
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(
+			countReply(t, 1), // user is in the project
+			docsReply(t, models.Project{ID: projectID, OwnerID: userID, Status: models.ProjectActive}),
+			countReply(t, 0), // status has no issues
+			countReply(t, 1), // workflow references the status
+		)
+
+		err := GetStatusService().DeleteStatus(done, projectID, userID)
+		if err == nil || !strings.Contains(err.Error(), "workflow") {
+			t.Fatalf("DeleteStatus = %v, want workflow error", err)
+		}
+		if slices.Contains(sentCommands(mt), "delete") {
+			t.Fatal("status referenced by the workflow was deleted")
+		}
+
+		mt.AddMockResponses(
+			docsReply(t, workflow),
+			docsReply(t, models.Status{ID: todo, ProjectID: projectID, Name: "To Do"}),
+		)
+		issue := &models.Issue{ID: primitive.NewObjectID(), ProjectID: projectID, StatusID: todo}
+		to := &models.Status{ID: later, ProjectID: projectID, Name: "Later"}
+
+		err = GetWorkflowService().CheckTransition(context.Background(), issue, to, userID, "")
+		if err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Fatalf("CheckTransition = %v, want move to be rejected", err)
+		}
+	})
+}
+
+func TestReferencesStatusFilter(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	statusID := primitive.NewObjectID()
+
+	withMockDB(t, func(mt *mtest.T) {
+		mt.AddMockResponses(countReply(t, 0))
+
+		in, err := GetWorkflowService().ReferencesStatus(context.Background(), projectID, statusID)
+		if err != nil || in {
+			t.Fatalf("ReferencesStatus = %v, %v, want false", in, err)
+		}
+
+		pipeline := mt.GetStartedEvent().Command.Lookup("pipeline").String()
+		for _, field := range []string{"transitions.from_status_id", "transitions.to_status_id", statusID.Hex()} {
+			if !strings.Contains(pipeline, field) {
+				t.Errorf("count pipeline %s does not match on %s", pipeline, field)
+			}
+		}
+	})
+}
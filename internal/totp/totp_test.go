@@ -0,0 +1,73 @@
+package totp
+
+import (
+	"encoding/base32"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA-1 seed of RFC 6238 appendix B, "12345678901234567890".
+var rfcSecret = base32.StdEncoding.EncodeToString([]byte("12345678901234567890"))
+
+// The RFC lists 8-digit codes; the last 6 digits are the 6-digit codes.
+var rfcVectors = []struct {
+	unix int64
+	code string
+}{
+	{59, "287082"},
+	{1111111109, "081804"},
+	{1111111111, "050471"},
+	{1234567890, "005924"},
+	{2000000000, "279037"},
+	{20000000000, "353130"},
+}
+
+func TestCodeRFC6238(t *testing.T) {
+	for _, v := range rfcVectors {
+		code, err := Code(rfcSecret, Step(time.Unix(v.unix, 0)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if code != v.code {
+			t.Errorf("Code at %d = %s, want %s", v.unix, code, v.code)
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	at := time.Unix(1234567890, 0)
+	now := Step(at)
+
+	for offset := int64(-1); offset <= 1; offset++ {
+		code, _ := Code(rfcSecret, now+offset)
+		step, ok := Validate(rfcSecret, code, at)
+		if !ok || step != now+offset {
+			t.Errorf("code of step %+d: Validate = %d, %v, want %d, true", offset, step, ok, now+offset)
+		}
+	}
+	for _, offset := range []int64{-2, 2} {
+		code, _ := Code(rfcSecret, now+offset)
+		if _, ok := Validate(rfcSecret, code, at); ok {
+			t.Errorf("code of step %+d was accepted", offset)
+		}
+	}
+}
+
+func TestValidateFormat(t *testing.T) {
+	at := time.Unix(1234567890, 0)
+
+	if _, ok := Validate(rfcSecret, " 005 924 ", at); !ok {
+		t.Error("code with spaces was rejected")
+	}
+	for _, code := range []string{"", "05924", "0005924", "00592a", "005924\n1", "-05924", "+05924"} {
+		if _, ok := Validate(rfcSecret, code, at); ok {
+			t.Errorf("Validate accepted %q", code)
+		}
+	}
+}
+
+func TestValidateInvalidSecret(t *testing.T) {
+	if _, ok := Validate("not base32!", "123456", time.Now()); ok {
+		t.Error("invalid secret validated a code")
+	}
+}
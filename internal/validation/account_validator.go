@@ -0,0 +1,145 @@
+package validation
+
+import (
+	"managify/dto/request"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func ProfileUpdateValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.ProfileUpdateRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	name := strings.TrimSpace(req.FullName)
+	if name == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Full name is required",
+		})
+	}
+	if len(name) > 100 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Full name must be at most 100 characters",
+		})
+	}
+
+	return c.Next()
+}
+
+func PasswordChangeValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.PasswordChangeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.CurrentPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Current password is required",
+		})
+	}
+	if msg := PasswordError(req.NewPassword); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": msg,
+		})
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "New password must differ from the current password",
+		})
+	}
+
+	return c.Next()
+}
+
+func EmailChangeValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.EmailChangeRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if !emailPattern.MatchString(req.Email) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid email format",
+		})
+	}
+	if req.CurrentPassword == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Current password is required",
+		})
+	}
+
+	return c.Next()
+}
+
+func AccountDeleteValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.AccountDeleteRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.Password == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Password is required",
+		})
+	}
+	for _, t := range req.Transfers {
+		if t.ProjectID == "" || t.NewOwnerID == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Each transfer needs a project_id and a new_owner_id",
+			})
+		}
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,41 @@
+package validation
+
+import (
+	"managify/dto/request"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func CommentValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.CommentRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.TrimSpace(req.Body) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Comment body is required",
+		})
+	}
+	if len(req.Body) > 2000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Comment body must be at most 2000 characters",
+		})
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,71 @@
+package validation
+
+import (
+	"managify/dto/request"
+	"managify/models"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func UpdateIssueValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.IssueUpdateRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.Title != nil {
+		if strings.TrimSpace(*req.Title) == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Issue title cannot be empty",
+			})
+		}
+		if len(*req.Title) > 200 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Issue title must be at most 200 characters",
+			})
+		}
+	}
+
+	if req.Description != nil && len(*req.Description) > 5000 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Issue description must be at most 5000 characters",
+		})
+	}
+
+	if req.Priority != nil && !models.PriorityType(*req.Priority).IsValid() {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Priority must be one of DEFAULT, MEDIUM, HIGH, URGENT, CRITICAL",
+		})
+	}
+
+	if req.Status != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Status cannot be changed here, move the issue with PUT /v1/board/move-issue/:issueID",
+		})
+	}
+
+	if req.DueDate != nil && *req.DueDate != "" {
+		if _, err := time.Parse("2006-01-02", *req.DueDate); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Due date must be in YYYY-MM-DD format",
+			})
+		}
+	}
+
+	return c.Next()
+}
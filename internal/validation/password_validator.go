@@ -0,0 +1,69 @@
+package validation
+
+import (
+	"managify/dto/request"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sirupsen/logrus"
+)
+
+func PasswordResetRequestValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.PasswordResetRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.TrimSpace(req.Email) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Email is required",
+		})
+	}
+
+	return c.Next()
+}
+
+func PasswordResetConfirmValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.PasswordResetConfirmRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.Token == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Reset token is required",
+		})
+	}
+
+	if msg := PasswordError(req.Password); msg != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": msg,
+		})
+	}
+
+	return c.Next()
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"managify/database"
+	"managify/dto/request"
 	"managify/models"
 	"time"
 
@@ -95,3 +96,62 @@ func validateUserId(id primitive.ObjectID) error {
 
 	return nil
 }
+
+func UpdateProjectValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.ProjectUpdateRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if req.Name != nil {
+		if *req.Name == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Project name is required",
+			})
+		}
+		if len(*req.Name) > 100 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Project name must be at most 100 characters",
+			})
+		}
+	}
+
+	if req.Tags != nil && len(*req.Tags) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "At least one tag is required",
+		})
+	}
+
+	if req.Description != nil {
+		if *req.Description == "" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Project description is required",
+			})
+		}
+		if len(*req.Description) > 500 {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Project description must be at most 500 characters",
+			})
+		}
+	}
+
+	if req.Category != nil && *req.Category == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Project category is required",
+		})
+	}
+
+	return c.Next()
+}
@@ -14,6 +14,8 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 )
 
+var emailPattern = regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
+
 func CreateRegisterValidator(c *fiber.Ctx) error {
 	us := service.GetUserService()
 	log := logrus.New()
@@ -34,27 +36,14 @@ func CreateRegisterValidator(c *fiber.Ctx) error {
 	}
 
 	// Password validation
-	if user.Password == "" {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "Password is required",
-		})
-	}
-
-	if len(user.Password) < 6 || len(user.Password) > 20 {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "Password must be between 6 and 20 characters",
-		})
-	}
-
-	if CheckPasswordComplexity(user.Password) {
+	if msg := PasswordError(user.Password); msg != "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "Password must contain at least 1 number, 1 uppercase letter, and 1 special character",
+			"message": msg,
 		})
 	}
 
 	// Email format
-	emailRegex := regexp.MustCompile(`^[a-z0-9._%+\-]+@[a-z0-9.\-]+\.[a-z]{2,}$`)
-	if !emailRegex.MatchString(user.Email) {
+	if !emailPattern.MatchString(user.Email) {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"message": "Invalid email format",
 		})
@@ -95,6 +84,20 @@ func CreateRegisterValidator(c *fiber.Ctx) error {
 	return c.Next()
 }
 
+// PasswordError returns why password is not acceptable, or "" if it is.
+func PasswordError(password string) string {
+	if password == "" {
+		return "Password is required"
+	}
+	if len(password) < 6 || len(password) > 20 {
+		return "Password must be between 6 and 20 characters"
+	}
+	if !CheckPasswordComplexity(password) {
+		return "Password must contain at least 1 number, 1 uppercase letter, and 1 special character"
+	}
+	return ""
+}
+
 func CheckPasswordComplexity(password string) bool {
 	hasNumber := false
 	hasUpper := false
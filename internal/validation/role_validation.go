@@ -1,7 +1,9 @@
 package validation
 
 import (
+	"managify/dto/request"
 	"managify/models"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sirupsen/logrus"
@@ -35,3 +37,47 @@ func CreateRoleValidator(c *fiber.Ctx) error {
 
 	return c.Next()
 }
+
+func RoleDefinitionValidator(c *fiber.Ctx) error {
+	log := logrus.New()
+	log.SetFormatter(&logrus.TextFormatter{
+		FullTimestamp: true,
+		ForceColors:   true,
+	})
+	log.SetLevel(logrus.InfoLevel)
+
+	var req request.RoleDefinitionRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		log.WithError(err).Error("Failed to parse request body")
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Invalid request body",
+			"error":   err.Error(),
+		})
+	}
+
+	if strings.TrimSpace(req.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Role name is required",
+		})
+	}
+	if len(req.Name) > 50 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "Role name must be at most 50 characters",
+		})
+	}
+	if len(req.Permissions) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"message": "At least one permission is required",
+		})
+	}
+	for _, p := range req.Permissions {
+		if !models.Permission(p).IsValid() {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"message": "Invalid permission: " + p,
+			})
+		}
+	}
+
+	return c.Next()
+}
@@ -0,0 +1,54 @@
+// Package webhook signs outgoing webhook deliveries and verifies them on the
+// receiving side.
+//
+// Every delivery carries the headers below. The signature is the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" keyed with the webhook secret, prefixed
+// with "sha256=". Receivers should also reject timestamps that are too old to
+// prevent replays.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	HeaderEvent     = "X-Managify-Event"
+	HeaderDelivery  = "X-Managify-Delivery"
+	HeaderTimestamp = "X-Managify-Timestamp"
+	HeaderSignature = "X-Managify-Signature"
+
+	signaturePrefix = "sha256="
+)
+
+// Sign returns the signature header value for a body sent at timestamp.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery. Requests
+// older than tolerance are rejected; a zero tolerance skips the check.
+func Verify(secret, timestamp, signature string, body []byte, tolerance time.Duration) bool {
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return false
+	}
+	if tolerance > 0 {
+		age := time.Since(time.Unix(ts, 0))
+		if age > tolerance || age < -tolerance {
+			return false
+		}
+	}
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Sign(secret, ts, body)), []byte(signature))
+}
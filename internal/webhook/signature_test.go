@@ -0,0 +1,75 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testSecret = "whsec_test"
+
+func TestSignVerifyRoundTrip(t *testing.T) {
+	body := []byte(`{"event":"issue.created"}`)
+	now := time.Now().Unix()
+	sig := Sign(testSecret, now, body)
+
+	if !strings.HasPrefix(sig, "sha256=") || len(sig) != len("sha256=")+64 {
+		t.Fatalf("signature %q is not a sha256 hex digest", sig)
+	}
+	if !Verify(testSecret, strconv.FormatInt(now, 10), sig, body, 5*time.Minute) {
+		t.Fatal("valid signature was rejected")
+	}
+}
+
+func TestSignKnownValue(t *testing.T) {
+	// HMAC-SHA256 of "1700000000.{}" keyed with "whsec_test", so receivers in
+	// other languages can check their implementation against it.
+	const want = "sha256=35495024f4ef3f94e5a93e22221544c4b75e9a42300cd965ab81cb85cd994e91"
+	if got := Sign(testSecret, 1700000000, []byte("{}")); got != want {
+		t.Errorf("Sign = %s, want %s", got, want)
+	}
+}
+
+func TestVerifyRejectsTampering(t *testing.T) {
+	body := []byte(`{"event":"issue.created","data":{"id":1}}`)
+	now := time.Now().Unix()
+	ts := strconv.FormatInt(now, 10)
+	sig := Sign(testSecret, now, body)
+
+	tests := []struct {
+		name            string
+		secret, ts, sig string
+		body            []byte
+	}{
+		{"tampered body", testSecret, ts, sig, []byte(`{"event":"issue.created","data":{"id":2}}`)},
+		{"wrong secret", "whsec_other", ts, sig, body},
+		{"shifted timestamp", testSecret, strconv.FormatInt(now+1, 10), sig, body},
+		{"missing prefix", testSecret, ts, strings.TrimPrefix(sig, "sha256="), body},
+		{"invalid timestamp", testSecret, "soon", sig, body},
+		{"empty signature", testSecret, ts, "", body},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if Verify(tt.secret, tt.ts, tt.sig, tt.body, 5*time.Minute) {
+				t.Fatal("signature was accepted")
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsStaleTimestamp(t *testing.T) {
+	body := []byte(`{}`)
+	for _, age := range []time.Duration{-10 * time.Minute, 10 * time.Minute} {
+		at := time.Now().Add(-age).Unix()
+		sig := Sign(testSecret, at, body)
+		ts := strconv.FormatInt(at, 10)
+
+		if Verify(testSecret, ts, sig, body, 5*time.Minute) {
+			t.Errorf("signature %s old was accepted", age)
+		}
+		if !Verify(testSecret, ts, sig, body, 0) {
+			t.Errorf("zero tolerance did not skip the age check for %s", age)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"managify/database"
 	"managify/internal/middleware"
 	"managify/internal/router"
+	"managify/internal/service"
 	"os"
 	"strconv"
 	"time"
@@ -38,6 +39,14 @@ func main() {
 
 	if err := database.Connect(); err != nil {
 		logrus.Infoln("Database connection failed: ", err)
+	} else {
+		if err := service.EnsureIndexes(); err != nil {
+			logrus.Warn("Failed to ensure database indexes: ", err)
+		}
+		middleware.TokenRevoked = service.GetAuthService().IsAccessTokenRevoked
+		middleware.UserVerified = service.GetUserService().IsVerified
+		middleware.APIKeyAuth = service.GetAPIKeyService().Authenticate
+		startBackgroundJobs()
 	}
 
 	app := fiber.New()
@@ -59,7 +68,7 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     allowOrigins,
 		AllowMethods:     allowMethods,
-		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+		AllowHeaders:     "Origin, Content-Type, Accept, Authorization, X-API-Key",
 		AllowCredentials: allowCredentials,
 	}))
 
@@ -80,6 +89,32 @@ func main() {
 
 }
 
+func startBackgroundJobs() {
+	purgeMinutes, _ := strconv.Atoi(os.Getenv("PROJECT_PURGE_INTERVAL_MINUTES"))
+	if purgeMinutes <= 0 {
+		purgeMinutes = 60
+	}
+	service.StartProjectPurger(time.Duration(purgeMinutes) * time.Minute)
+
+	outboxSeconds, _ := strconv.Atoi(os.Getenv("MAIL_OUTBOX_INTERVAL_SECONDS"))
+	if outboxSeconds <= 0 {
+		outboxSeconds = 15
+	}
+	service.StartMailOutbox(time.Duration(outboxSeconds) * time.Second)
+
+	notifyMinutes, _ := strconv.Atoi(os.Getenv("NOTIFICATION_INTERVAL_MINUTES"))
+	if notifyMinutes <= 0 {
+		notifyMinutes = 5
+	}
+	service.StartNotificationWorker(time.Duration(notifyMinutes) * time.Minute)
+
+	webhookSeconds, _ := strconv.Atoi(os.Getenv("WEBHOOK_INTERVAL_SECONDS"))
+	if webhookSeconds <= 0 {
+		webhookSeconds = 15
+	}
+	service.StartWebhookDispatcher(time.Duration(webhookSeconds) * time.Second)
+}
+
 func apiLimiter(app *fiber.App) {
 
 	apiMaxLimiter, _ := strconv.Atoi(os.Getenv("API_MAX_LIMITER"))
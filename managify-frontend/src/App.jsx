@@ -15,6 +15,7 @@ import { Toaster } from "react-hot-toast";
 import ProjectDetail from "./components/project/DetailProject";
 import Profile from "./components/main/Profile";
 import VerifyEmail from "./components/verify/VerifyEmail";
+import SsoCallback from "./components/login/SsoCallback";
 import { ThemeProvider } from "./content/ThemeContent";
 
 const isElectron = window?.process?.versions?.electron;
@@ -67,6 +68,14 @@ export default function App() {
                 </PublicRoute>
               }
             />
+            <Route
+              path="/sso/callback"
+              element={
+                <PublicRoute>
+                  <SsoCallback />
+                </PublicRoute>
+              }
+            />
             <Route
               path="/dashboard"
               element={
@@ -336,7 +336,6 @@ export default function ManagifyDashboard() {
                             )}
                             {!userData.isverified && (
                                 <EmailVerificationGuard
-                                    token={userData.verificationtoken}
                                     userData={userData}
                                     isDarkMode={isDarkMode}
                                 />
@@ -1,5 +1,6 @@
 import { Tag, Typography, Button } from "antd";
 import InviteBell from "./InviteBell";
+import NotificationBell from "./NotificationBell";
 
 const { Title, Text } = Typography;
 
@@ -30,6 +31,7 @@ export default function DashboardHeader({ firstName, userProjects, subscriptionD
 
                 {/* Bell component */}
                 <InviteBell userID={userID} token={token} />
+                <NotificationBell token={token} />
             </div>
         </div>
     )
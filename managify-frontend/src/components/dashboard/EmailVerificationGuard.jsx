@@ -3,20 +3,16 @@ import { Alert, Button, Modal, message } from 'antd';
 import { MailOutlined, WarningOutlined } from '@ant-design/icons';
 import { api } from '../api/api';
 
-export default function EmailVerificationGuard({ token, userData, isDarkMode }) {
+export default function EmailVerificationGuard({ userData, isDarkMode }) {
   const [isResending, setIsResending] = useState(false);
 
   const handleResendEmail = async () => {
     setIsResending(true);
     try {
-      console.log("token inside resend email" + token)
-      await api.get('/users/verify-email', {
-        params: { token }
-      });
-      console.log("given token" + token)
+      await api.post('/users/resend-verification');
       message.success('Verification email sent! Please check your inbox.');
     } catch (error) {
-      message.error('Failed to send verification email. Please try again.');
+      message.error(error.response?.data?.error || 'Failed to send verification email. Please try again.');
       console.error(error);
     } finally {
       setIsResending(false);
@@ -37,13 +33,18 @@ export default function EmailVerificationGuard({ token, userData, isDarkMode })
               Your email address <strong>{userData?.email}</strong> is not verified yet.
               Please verify your email to access all features including creating projects and editing your profile.
             </p>
-            <p className=' text-sm'>After registering, you’ll receive an email to verify your account. If you haven’t received it yet, please log out and log back in — we’ll send the verification email again!</p>
+            <p className=' text-sm'>After registering, you’ll receive an email to verify your account. The link expires after a while; if it has expired or you haven’t received it, request a new one below.</p>
           </div>
         }
         type="warning"
         showIcon
         icon={<WarningOutlined />}
         closable={false}
+        action={
+          <Button size="small" type="primary" icon={<MailOutlined />} loading={isResending} onClick={handleResendEmail}>
+            Resend Email
+          </Button>
+        }
       />
     </div>
   );
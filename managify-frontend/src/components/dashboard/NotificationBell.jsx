@@ -0,0 +1,102 @@
+import { useEffect, useState } from "react";
+import { Badge, Button, Popover, Spin, Typography } from "antd";
+import { NotificationOutlined } from "@ant-design/icons";
+
+import { api } from "../api/api";
+import { NOTIFICATIONS, NOTIFICATIONS_READ_ALL, NOTIFICATIONS_UNREAD_COUNT } from "../../constants/urls";
+
+const { Text } = Typography;
+
+export default function NotificationBell({ token }) {
+  const [notifications, setNotifications] = useState([]);
+  const [unread, setUnread] = useState(0);
+  const [loading, setLoading] = useState(false);
+
+  const headers = { Authorization: `Bearer ${token}` };
+
+  const fetchUnreadCount = async () => {
+    try {
+      const res = await api.get(NOTIFICATIONS_UNREAD_COUNT, { headers });
+      setUnread(res.data.data.unread_count);
+    } catch (err) {
+      console.error(err);
+    }
+  };
+
+  useEffect(() => {
+    if (!token) return;
+    fetchUnreadCount();
+    const timer = setInterval(fetchUnreadCount, 60000);
+    return () => clearInterval(timer);
+  }, [token]);
+
+  const fetchNotifications = async () => {
+    setLoading(true);
+    try {
+      const res = await api.get(NOTIFICATIONS, { headers });
+      setNotifications(res.data.data);
+      setUnread(res.data.unread_count);
+    } catch (err) {
+      console.error(err);
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const toggleRead = async (notification) => {
+    const action = notification.read ? "unread" : "read";
+    try {
+      const res = await api.put(`${NOTIFICATIONS}${notification.id}/${action}`, null, { headers });
+      setNotifications((list) => list.map((n) => (n.id === notification.id ? res.data.data : n)));
+      setUnread((count) => count + (notification.read ? 1 : -1));
+    } catch (err) {
+      console.error(err);
+    }
+  };
+
+  const markAllRead = async () => {
+    try {
+      await api.put(NOTIFICATIONS_READ_ALL, null, { headers });
+      setNotifications((list) => list.map((n) => ({ ...n, read: true })));
+      setUnread(0);
+    } catch (err) {
+      console.error(err);
+    }
+  };
+
+  const content = (
+    <div style={{ width: 340, maxHeight: 420, overflowY: "auto" }}>
+      <div className="flex justify-between items-center mb-2">
+        <Text strong>Notifications</Text>
+        <Button type="link" size="small" onClick={markAllRead} disabled={unread === 0}>
+          Mark all as read
+        </Button>
+      </div>
+      {loading ? (
+        <div className="flex justify-center p-4"><Spin /></div>
+      ) : notifications.length === 0 ? (
+        <Text className="p-4 block">No notifications</Text>
+      ) : (
+        notifications.map((n) => (
+          <div
+            key={n.id}
+            className={`mb-2 p-2 border-b cursor-pointer ${n.read ? "" : "bg-blue-50"}`}
+            onClick={() => toggleRead(n)}
+          >
+            <Text strong={!n.read}>{n.title}</Text>
+            <div><Text type="secondary">{n.message}</Text></div>
+            <Text type="secondary" className="text-xs">{new Date(n.created_at).toLocaleString()}</Text>
+          </div>
+        ))
+      )}
+    </div>
+  );
+
+  return (
+    <Popover content={content} trigger="click" placement="bottomRight" onOpenChange={(open) => open && fetchNotifications()}>
+      <Badge count={unread} size="small">
+        <Button icon={<NotificationOutlined />} />
+      </Badge>
+    </Popover>
+  );
+}
@@ -1,14 +1,24 @@
 import { useContext, useState } from "react";
 import { Form, Input, Button, message } from "antd";
 import { Link, useNavigate } from "react-router-dom";
-import { LOGIN } from "../../constants/urls";
+import { LOGIN, LOGIN_2FA, LOGIN_OIDC } from "../../constants/urls";
 import { api } from "../api/api";
 import { AuthContext } from "../../content/AuthContent";
 import { toast } from 'react-hot-toast';
 export default function LoginForm() {
   const [loading, setLoading] = useState(false);
+  const [challenge, setChallenge] = useState(null);
   const { setToken } = useContext(AuthContext);
   const navigate = useNavigate();
+  const completeLogin = (data) => {
+    toast.success("Navigating")
+    localStorage.setItem("token", data.token);
+    localStorage.setItem("refresh_token", data.refresh_token);
+    setToken(data.token);
+
+    navigate("/dashboard");
+  };
+
   const onFinish = async (values) => {
     setLoading(true);
     const userData = {
@@ -18,18 +28,78 @@ export default function LoginForm() {
     
     try {
       const response = await api.post(LOGIN, userData);
-      toast.success("Navigating")
-      localStorage.setItem("token", response.data.token);
-      setToken(response.data.token);
+      if (response.data.two_factor_required) {
+        setChallenge(response.data.challenge_token);
+        return;
+      }
+      completeLogin(response.data);
+    } catch (error) {
+      toast.error(error.response?.data?.error || "Failed")
+    } finally {
+      setLoading(false);
+    }
+  };
+
+  const onSso = async () => {
+    setLoading(true);
+    try {
+      const response = await api.get(LOGIN_OIDC);
+      window.location.href = response.data.data.authorization_url;
+    } catch (error) {
+      toast.error(error.response?.data?.error || "Single sign-on is unavailable")
+      setLoading(false);
+    }
+  };
 
-      navigate("/dashboard");
+  const onSubmitCode = async (values) => {
+    setLoading(true);
+    try {
+      const response = await api.post(LOGIN_2FA, {
+        challenge_token: challenge,
+        code: values.code,
+      });
+      completeLogin(response.data);
     } catch (error) {
-      toast.error("Failed")
+      toast.error(error.response?.data?.error || "Failed")
     } finally {
       setLoading(false);
     }
   };
 
+  if (challenge) {
+    return (
+      <div className="flex justify-center py-12">
+        <div className="w-96 p-6 border border-gray-300 rounded-lg shadow-md bg-white">
+          <h2 className="text-center text-2xl font-semibold mb-5">Two-Factor Authentication</h2>
+          <Form layout="vertical" name="two-factor" onFinish={onSubmitCode}>
+            <Form.Item
+              label="Authentication code"
+              name="code"
+              extra="Enter the code from your authenticator app or a recovery code."
+              rules={[{ required: true, message: "Please input your code!" }]}
+            >
+              <Input placeholder="123456" autoComplete="one-time-code" className="!rounded-md !border-gray-300" />
+            </Form.Item>
+            <Form.Item>
+              <Button
+                type="primary"
+                htmlType="submit"
+                loading={loading}
+                block
+                className="!bg-blue-600 !hover:bg-blue-700 !text-white !font-semibold !rounded-md"
+              >
+                Verify
+              </Button>
+            </Form.Item>
+          </Form>
+          <Button type="link" block onClick={() => setChallenge(null)}>
+            Back to login
+          </Button>
+        </div>
+      </div>
+    )
+  }
+
   return (
     <div className="flex justify-center py-12">
       <div className="w-96 p-6 border border-gray-300 rounded-lg shadow-md bg-white">
@@ -64,6 +134,9 @@ export default function LoginForm() {
             </Button>
           </Form.Item>
         </Form>
+        <Button block loading={loading} onClick={onSso} className="!rounded-md !mb-4">
+          Sign in with SSO
+        </Button>
         <p className="text-sm opacity-50 ">
           Do you have an account?{" "}
           <Link to="/register" className="text-blue-600 hover:underline">
@@ -0,0 +1,54 @@
+import { useContext, useEffect, useRef, useState } from "react";
+import { Link, useNavigate, useSearchParams } from "react-router-dom";
+import { api } from "../api/api";
+import { LOGIN_OIDC_CALLBACK } from "../../constants/urls";
+import { AuthContext } from "../../content/AuthContent";
+
+export default function SsoCallback() {
+  const [searchParams] = useSearchParams();
+  const [message, setMessage] = useState("");
+  const hasCalledRef = useRef(false);
+  const { setToken } = useContext(AuthContext);
+  const navigate = useNavigate();
+  const code = searchParams.get("code");
+  const state = searchParams.get("state");
+  const providerError = searchParams.get("error_description") || searchParams.get("error");
+
+  useEffect(() => {
+    if (providerError || !code || !state) {
+      setMessage(providerError || "The identity provider did not return a login code.");
+      return;
+    }
+
+    if (hasCalledRef.current) return;
+    hasCalledRef.current = true;
+
+    api.post(LOGIN_OIDC_CALLBACK, { code, state })
+      .then((response) => {
+        localStorage.setItem("token", response.data.token);
+        localStorage.setItem("refresh_token", response.data.refresh_token);
+        setToken(response.data.token);
+        navigate("/dashboard");
+      })
+      .catch((error) => {
+        setMessage(error.response?.data?.error || "Single sign-on failed.");
+      });
+  }, [code, state, providerError, setToken, navigate]);
+
+  return (
+    <div className="flex flex-col items-center justify-center min-h-screen bg-gray-100">
+      <div className="bg-white p-8 rounded shadow-md w-full max-w-md">
+        <h1 className="text-2xl font-bold mb-6 text-center">Single Sign-On</h1>
+        {!message && <p className="mb-4 text-center">Signing you in...</p>}
+        {message && (
+          <>
+            <p className="mb-4 text-center text-red-600">{message}</p>
+            <Link to="/login" className="block text-center text-blue-600 hover:underline">
+              Back to login
+            </Link>
+          </>
+        )}
+      </div>
+    </div>
+  );
+}
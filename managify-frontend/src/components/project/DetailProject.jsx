@@ -48,6 +48,7 @@ import { api } from "../api/api";
 import { toast } from 'react-hot-toast';
 import CreateIssueModal from "./CreateIssueModal";
 import { useTheme } from "../../content/ThemeContent";
+import { BASE, VERSION, REALTIME_PROJECT, REALTIME_EVENTS } from "../../constants/urls";
 
 const { Title, Text, Paragraph } = Typography;
 
@@ -79,6 +80,8 @@ export default function ProjectDetail() {
 
     const [onDue, setOnDue] = useState([]);
 
+    const [reloadKey, setReloadKey] = useState(0);
+
     const { isDarkMode, toggleTheme } = useTheme();
 
     // Fetch oncoming issues
@@ -119,6 +122,19 @@ export default function ProjectDetail() {
             }
         };
         fetchProject();
+    }, [id, token, reloadKey]);
+
+    // Reload the board when a teammate changes it
+    useEffect(() => {
+        if (!token) return;
+        const source = new EventSource(`${BASE}${VERSION}${REALTIME_PROJECT}${id}?access_token=${encodeURIComponent(token)}`);
+        const reload = () => {
+            setFetchedStatuses({});
+            setReloadKey(key => key + 1);
+        };
+        REALTIME_EVENTS.forEach(type => source.addEventListener(type, reload));
+        source.addEventListener("expired", () => source.close());
+        return () => source.close();
     }, [id, token]);
 
     // Fetch issues for each status
@@ -149,7 +165,7 @@ export default function ProjectDetail() {
             const token = localStorage.getItem('token');
             if (!token) return;
 
-            await api.delete(`project/projects/member/${memberId}`, {
+            await api.delete(`project/projects/${project.project.id}/member/${memberId}`, {
                 headers: { Authorization: `Bearer ${token}` }
             });
 
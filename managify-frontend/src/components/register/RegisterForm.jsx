@@ -31,7 +31,7 @@ export default function RegisterForm() {
       navigate("/dashboard"); 
     } catch (error) {
       toast.error("Failed")
-      message.error("Failed to register user!");
+      message.error(error.response?.data?.message || "Failed to register user!");
     } finally {
       setLoading(false);
     }
@@ -62,7 +62,17 @@ export default function RegisterForm() {
           <Form.Item
             label="Password"
             name="password"
-            rules={[{ required: true, message: "Please input your password!" }]}
+            extra="6-20 characters with at least 1 number, 1 uppercase letter and 1 special character."
+            rules={[
+              { required: true, message: "Please input your password!" },
+              { min: 6, max: 20, message: "Password must be between 6 and 20 characters" },
+              {
+                validator: (_, value) =>
+                  !value || (/[0-9]/.test(value) && /[A-Z]/.test(value) && /[!-/:-@[-`{-~]/.test(value))
+                    ? Promise.resolve()
+                    : Promise.reject(new Error("Password must contain at least 1 number, 1 uppercase letter, and 1 special character")),
+              },
+            ]}
           >
             <Input.Password placeholder="Enter your password" className="!rounded-md !border-gray-300" />
           </Form.Item>
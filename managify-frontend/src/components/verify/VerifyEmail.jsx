@@ -11,6 +11,7 @@ export default function VerifyEmail() {
   const [message, setMessage] = useState("");
   const hasCalledRef = useRef(false); 
   const token = searchParams.get("token");
+  const isEmailChange = searchParams.get("change") === "1";
 
   console.log("Token from URL:", token);
 
@@ -25,10 +26,15 @@ export default function VerifyEmail() {
     if (hasCalledRef.current) return;
     hasCalledRef.current = true;
 
-    api.get(`/users/verify-email`, { params: { token } })
+    const endpoint = isEmailChange ? `/users/confirm-email` : `/users/verify-email`;
+    api.get(endpoint, { params: { token } })
       .then(() => {
         setStatus("success");
-        setMessage("Your email has been verified! You can now log in.");
+        setMessage(
+          isEmailChange
+            ? "Your email has been changed. Use the new address to log in."
+            : "Your email has been verified! You can now log in."
+        );
       })
       .catch((error) => {
         setStatus("error");
@@ -37,7 +43,7 @@ export default function VerifyEmail() {
           error.response?.data?.message || "Verification failed."
         );
       });
-  }, [token]);
+  }, [token, isEmailChange]);
 
   return (
     <div className="flex flex-col items-center justify-center min-h-screen bg-gray-100">
@@ -4,5 +4,16 @@ export const VERSION = "v1/"
 
 export const REGISTER = "users/register"
 export const LOGIN = "users/auth"
+export const LOGIN_2FA = "users/auth/2fa"
+export const LOGIN_OIDC = "users/auth/oidc"
+export const LOGIN_OIDC_CALLBACK = "users/auth/oidc/callback"
+export const LOGOUT = "users/logout"
 
 export const CREATE_PROJECT="project/create-project"
+
+export const NOTIFICATIONS = "notifications/"
+export const NOTIFICATIONS_UNREAD_COUNT = "notifications/unread-count"
+export const NOTIFICATIONS_READ_ALL = "notifications/read-all"
+
+export const REALTIME_PROJECT = "realtime/projects/"
+export const REALTIME_EVENTS = ["issue.created", "issue.updated", "issue.moved", "issue.deleted", "status.created", "status.deleted", "member.joined"]
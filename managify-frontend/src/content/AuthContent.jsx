@@ -1,6 +1,8 @@
 import { createContext, useState, useEffect, useCallback } from "react";
 import { jwtDecode } from "jwt-decode";
 import { useNavigate } from "react-router-dom";
+import { api } from "../components/api/api";
+import { LOGOUT } from "../constants/urls";
 
 export const AuthContext = createContext();
 
@@ -15,7 +17,15 @@ export function AuthProvider({ children }) {
   }, []);
 
   const logout = useCallback(() => {
+    const storedToken = localStorage.getItem("token");
+    const refreshToken = localStorage.getItem("refresh_token");
+    if (storedToken) {
+      api.post(LOGOUT, { refresh_token: refreshToken }, {
+        headers: { Authorization: `Bearer ${storedToken}` }
+      }).catch(() => {});
+    }
     localStorage.removeItem("token");
+    localStorage.removeItem("refresh_token");
     setToken(null);
     navigate('/login', { replace: true });
   }, [navigate]);
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// APIKeyPrefix starts every personal API key, which tells them apart from
+// JWTs in the Authorization header.
+const APIKeyPrefix = "mfy_"
+
+type APIKeyScope string
+
+const (
+	// ScopeRead allows GET requests only.
+	ScopeRead APIKeyScope = "read"
+	// ScopeWrite allows every request the owner could make.
+	ScopeWrite APIKeyScope = "write"
+	// ScopeAdmin keeps the admin rights of an admin owner.
+	ScopeAdmin APIKeyScope = "admin"
+)
+
+var APIKeyScopes = []APIKeyScope{ScopeRead, ScopeWrite, ScopeAdmin}
+
+func (s APIKeyScope) IsValid() bool {
+	return slices.Contains(APIKeyScopes, s)
+}
+
+// APIKey is a personal access key for scripts. Only the hash of the key is
+// stored; Prefix identifies it in listings.
+type APIKey struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Name       string             `bson:"name" json:"name"`
+	Prefix     string             `bson:"prefix" json:"prefix"`
+	KeyHash    string             `bson:"key_hash" json:"-"`
+	Scopes     []APIKeyScope      `bson:"scopes" json:"scopes"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	LastUsedAt *time.Time         `bson:"last_used_at,omitempty" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,27 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AuditAction string
+
+const (
+	AuditLoginLocked   AuditAction = "login.locked"
+	AuditLoginUnlocked AuditAction = "login.unlocked"
+)
+
+// AuditLog records a security relevant event that does not belong to a
+// project. ActorID is empty for events triggered by anonymous requests.
+type AuditLog struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action    AuditAction        `bson:"action" json:"action"`
+	ActorID   primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	UserID    primitive.ObjectID `bson:"user_id,omitempty" json:"user_id,omitempty"`
+	Email     string             `bson:"email,omitempty" json:"email,omitempty"`
+	IP        string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	Message   string             `bson:"message" json:"message"`
+	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
+}
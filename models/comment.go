@@ -0,0 +1,20 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Comment struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IssueID   primitive.ObjectID `bson:"issue_id" json:"issue_id"`
+	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
+	AuthorID  primitive.ObjectID `bson:"author_id" json:"author_id"`
+	ParentID  primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
+	Body      string             `bson:"body" json:"body"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	EditedAt  *time.Time         `bson:"edited_at,omitempty" json:"edited_at,omitempty"`
+	IsDeleted bool               `bson:"is_deleted" json:"is_deleted"`
+	DeletedAt *time.Time         `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+}
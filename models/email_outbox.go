@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type EmailStatus string
+
+const (
+	EmailPending EmailStatus = "pending"
+	EmailSending EmailStatus = "sending"
+	EmailSent    EmailStatus = "sent"
+	EmailFailed  EmailStatus = "failed"
+)
+
+// OutboxEmail is a rendered email waiting for delivery or kept as a record of
+// it. The bodies can contain one-time links and are removed once sent, or once
+// delivery failed for good if they do.
+type OutboxEmail struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	To            string             `bson:"to" json:"to"`
+	Template      string             `bson:"template" json:"template"`
+	Subject       string             `bson:"subject" json:"subject"`
+	HTML          string             `bson:"html,omitempty" json:"-"`
+	Text          string             `bson:"text,omitempty" json:"-"`
+	Status        EmailStatus        `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	LastError     string             `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	SentAt        *time.Time         `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	FailedAt      *time.Time         `bson:"failed_at,omitempty" json:"failed_at,omitempty"`
+}
@@ -1,6 +1,10 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type PriorityType string
 type StatusType string
@@ -31,5 +35,27 @@ type Issue struct {
 	DueDate     string               `bson:"due_date,omitempty" json:"due_date"`
 	Tags        []string             `bson:"tags,omitempty" json:"tags"`
 	StatusID    primitive.ObjectID   `bson:"status_id,omitempty" json:"status_id"`
+	AssigneeID  primitive.ObjectID   `bson:"assignee_id,omitempty" json:"assignee_id,omitempty"`
+	Resolution  string               `bson:"resolution,omitempty" json:"resolution,omitempty"`
 	CommentIDs  []primitive.ObjectID `bson:"comments,omitempty" json:"-"`
+	WatcherIDs  []primitive.ObjectID `bson:"watchers,omitempty" json:"watchers,omitempty"`
+	// DueReminderFor is the due date the due-soon notification was sent for.
+	DueReminderFor string    `bson:"due_reminder_for,omitempty" json:"-"`
+	UpdatedAt      time.Time `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
+}
+
+func (p PriorityType) IsValid() bool {
+	switch p {
+	case Default, Medium, High, Urgent, Critical:
+		return true
+	}
+	return false
+}
+
+func (s StatusType) IsValid() bool {
+	switch s {
+	case TODO, IN_PROGRESS, REVIEW, DONE, BLOCKED:
+		return true
+	}
+	return false
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type FieldChange struct {
+	Field  string      `bson:"field" json:"field"`
+	Before interface{} `bson:"before" json:"before"`
+	After  interface{} `bson:"after" json:"after"`
+}
+
+type IssueHistory struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	IssueID   primitive.ObjectID `bson:"issue_id" json:"issue_id"`
+	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Changes   []FieldChange      `bson:"changes" json:"changes"`
+	Timestamp time.Time          `bson:"timestamp" json:"timestamp"`
+}
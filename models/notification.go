@@ -0,0 +1,98 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type NotificationEvent string
+
+const (
+	EventInviteReceived     NotificationEvent = "invite_received"
+	EventInviteAccepted     NotificationEvent = "invite_accepted"
+	EventInviteDeclined     NotificationEvent = "invite_declined"
+	EventIssueAssigned      NotificationEvent = "issue_assigned"
+	EventIssueStatusChanged NotificationEvent = "issue_status_changed"
+	EventIssueDueSoon       NotificationEvent = "issue_due_soon"
+
+	// Only shown in the in-app inbox, never emailed.
+	EventIssueCreated NotificationEvent = "issue_created"
+	EventRoleAssigned NotificationEvent = "role_assigned"
+)
+
+// NotificationEvents lists the events that can be emailed and muted.
+var NotificationEvents = []NotificationEvent{
+	EventInviteReceived, EventInviteAccepted, EventInviteDeclined,
+	EventIssueAssigned, EventIssueStatusChanged, EventIssueDueSoon,
+}
+
+func (e NotificationEvent) IsValid() bool {
+	return slices.Contains(NotificationEvents, e)
+}
+
+type DigestFrequency string
+
+const (
+	DigestInstant DigestFrequency = "instant"
+	DigestHourly  DigestFrequency = "hourly"
+	DigestDaily   DigestFrequency = "daily"
+)
+
+func (d DigestFrequency) IsValid() bool {
+	switch d {
+	case DigestInstant, DigestHourly, DigestDaily:
+		return true
+	}
+	return false
+}
+
+// NotificationPreferences decide which events are emailed to a user and
+// whether they arrive one by one or batched into a digest. The zero value
+// emails every event instantly.
+type NotificationPreferences struct {
+	EmailMuted []NotificationEvent `bson:"email_muted,omitempty" json:"email_muted"`
+	Digest     DigestFrequency     `bson:"digest,omitempty" json:"digest"`
+}
+
+// EmailEnabled reports whether the event is emailed at all.
+func (p NotificationPreferences) EmailEnabled(event NotificationEvent) bool {
+	return !slices.Contains(p.EmailMuted, event)
+}
+
+// DigestFrequency returns the digest setting, defaulting to instant.
+func (p NotificationPreferences) DigestFrequency() DigestFrequency {
+	if p.Digest == "" {
+		return DigestInstant
+	}
+	return p.Digest
+}
+
+// QueuedNotification waits in the digest queue until DigestAt, when all due
+// notifications of the user are sent as one email.
+type QueuedNotification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	Event     NotificationEvent  `bson:"event" json:"event"`
+	Title     string             `bson:"title" json:"title"`
+	Message   string             `bson:"message" json:"message"`
+	Link      string             `bson:"link,omitempty" json:"link,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	DigestAt  time.Time          `bson:"digest_at" json:"digest_at"`
+}
+
+// Notification is an entry of a user's in-app inbox.
+type Notification struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"-"`
+	ActorID   primitive.ObjectID `bson:"actor_id,omitempty" json:"actor_id,omitempty"`
+	ProjectID primitive.ObjectID `bson:"project_id,omitempty" json:"project_id,omitempty"`
+	Event     NotificationEvent  `bson:"event" json:"event"`
+	Title     string             `bson:"title" json:"title"`
+	Message   string             `bson:"message" json:"message"`
+	Link      string             `bson:"link,omitempty" json:"link,omitempty"`
+	Read      bool               `bson:"read" json:"read"`
+	ReadAt    *time.Time         `bson:"read_at,omitempty" json:"read_at,omitempty"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+}
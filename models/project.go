@@ -1,6 +1,19 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectStatus string
+
+const (
+	ProjectActive    ProjectStatus = "ACTIVE"
+	ProjectOnHold    ProjectStatus = "ON_HOLD"
+	ProjectArchived  ProjectStatus = "ARCHIVED"
+	ProjectCompleted ProjectStatus = "COMPLETED"
+)
 
 type Project struct {
 	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
@@ -10,5 +23,25 @@ type Project struct {
 	Tags        []string             `bson:"tags,omitempty" json:"tags"`
 	OwnerID     primitive.ObjectID   `bson:"owner_id,omitempty" json:"owner_id"`
 	TeamIDs     []primitive.ObjectID `bson:"team,omitempty" json:"teams_id"`
-	Status      string               `bson:"status" json:"status"`
+	Status      ProjectStatus        `bson:"status" json:"status"`
+	Template    string               `bson:"template,omitempty" json:"template,omitempty"`
+	DeletedAt   *time.Time           `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	PurgeAt     *time.Time           `bson:"purge_at,omitempty" json:"purge_at,omitempty"`
+}
+
+func (s ProjectStatus) IsValid() bool {
+	switch s {
+	case ProjectActive, ProjectOnHold, ProjectArchived, ProjectCompleted:
+		return true
+	}
+	return false
+}
+
+// Lifecycle returns the project status, treating projects created before
+// lifecycle states existed as active.
+func (p *Project) Lifecycle() ProjectStatus {
+	if p.Status == "" {
+		return ProjectActive
+	}
+	return p.Status
 }
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateTransition is a WorkflowTransition referring to statuses by name,
+// resolved when the template is applied. An empty From matches any status.
+type TemplateTransition struct {
+	From           string   `bson:"from,omitempty" json:"from,omitempty"`
+	To             string   `bson:"to" json:"to"`
+	RequiredFields []string `bson:"required_fields,omitempty" json:"required_fields,omitempty"`
+	AllowedRoles   []string `bson:"allowed_roles,omitempty" json:"allowed_roles,omitempty"`
+}
+
+// ProjectTemplate seeds the statuses, tags and workflow of a new project.
+// Built-in templates live in code; custom ones are stored by admins.
+type ProjectTemplate struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id,omitempty"`
+	Key         string               `bson:"key" json:"key"`
+	Name        string               `bson:"name" json:"name"`
+	Description string               `bson:"description" json:"description"`
+	Statuses    []string             `bson:"statuses" json:"statuses"`
+	Tags        []string             `bson:"tags,omitempty" json:"tags"`
+	Transitions []TemplateTransition `bson:"transitions,omitempty" json:"transitions"`
+	BuiltIn     bool                 `bson:"-" json:"built_in"`
+	CreatedBy   primitive.ObjectID   `bson:"created_by,omitempty" json:"-"`
+	CreatedAt   time.Time            `bson:"created_at,omitempty" json:"created_at,omitempty"`
+}
@@ -1,10 +1,71 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ProjectRole string
+
+const (
+	RoleOwner      ProjectRole = "OWNER"
+	RoleMaintainer ProjectRole = "MAINTAINER"
+	RoleMember     ProjectRole = "MEMBER"
+	RoleViewer     ProjectRole = "VIEWER"
+)
+
+type Permission string
+
+const (
+	PermProjectView     Permission = "project.view"
+	PermProjectUpdate   Permission = "project.update"
+	PermProjectDelete   Permission = "project.delete"
+	PermIssueCreate     Permission = "issue.create"
+	PermIssueUpdate     Permission = "issue.update"
+	PermIssueDelete     Permission = "issue.delete"
+	PermIssueAssign     Permission = "issue.assign"
+	PermCommentCreate   Permission = "comment.create"
+	PermCommentModerate Permission = "comment.moderate"
+	PermStatusManage    Permission = "status.manage"
+	PermRoleManage      Permission = "role.manage"
+	PermInviteSend      Permission = "invite.send"
+	PermMemberRemove    Permission = "member.remove"
+	PermWebhookManage   Permission = "webhook.manage"
+)
+
+// AllPermissions lists every permission a role can be granted.
+var AllPermissions = []Permission{
+	PermProjectView, PermProjectUpdate, PermProjectDelete,
+	PermIssueCreate, PermIssueUpdate, PermIssueDelete, PermIssueAssign,
+	PermCommentCreate, PermCommentModerate,
+	PermStatusManage, PermRoleManage, PermInviteSend, PermMemberRemove,
+	PermWebhookManage,
+}
+
+func (p Permission) IsValid() bool {
+	return slices.Contains(AllPermissions, p)
+}
 
 type Role struct {
 	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
 	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
 	RoleName  string             `bson:"role" json:"role"`
+	// DefinitionID is set when RoleName refers to a custom RoleDefinition
+	// of the project rather than a built-in role.
+	DefinitionID primitive.ObjectID `bson:"definition_id,omitempty" json:"definition_id,omitempty"`
+}
+
+// RoleDefinition is a named, project-specific role granting a custom set of
+// permissions.
+type RoleDefinition struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Name        string             `bson:"name" json:"name"`
+	Permissions []Permission       `bson:"permissions" json:"permissions"`
+	CreatedBy   primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt   time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt   time.Time          `bson:"updated_at" json:"updated_at"`
 }
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// RefreshToken is one link in a chain of rotated refresh tokens. All tokens
+// descending from the same login share a FamilyID; only the hash of the
+// token is stored.
+type RefreshToken struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID     primitive.ObjectID `bson:"user_id" json:"user_id"`
+	FamilyID   primitive.ObjectID `bson:"family_id" json:"family_id"`
+	TokenHash  string             `bson:"token_hash" json:"-"`
+	UserAgent  string             `bson:"user_agent,omitempty" json:"user_agent,omitempty"`
+	IP         string             `bson:"ip,omitempty" json:"ip,omitempty"`
+	CreatedAt  time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt  time.Time          `bson:"expires_at" json:"expires_at"`
+	UsedAt     *time.Time         `bson:"used_at,omitempty" json:"used_at,omitempty"`
+	RevokedAt  *time.Time         `bson:"revoked_at,omitempty" json:"revoked_at,omitempty"`
+	ReplacedBy primitive.ObjectID `bson:"replaced_by,omitempty" json:"-"`
+}
+
+// RevokedToken blocks an access token by its jti until the token expires.
+type RevokedToken struct {
+	JTI       string             `bson:"_id" json:"jti"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// LoginChallenge is the pending second step of a login by a user with
+// two-factor authentication. Only the hash of the challenge token is stored.
+type LoginChallenge struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID    primitive.ObjectID `bson:"user_id" json:"user_id"`
+	TokenHash string             `bson:"token_hash" json:"-"`
+	Attempts  int                `bson:"attempts" json:"attempts"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt time.Time          `bson:"expires_at" json:"expires_at"`
+}
+
+// OIDCLogin is a single sign-on login waiting for the identity provider to
+// redirect back. It is found by the hash of its state parameter and holds the
+// nonce and PKCE code verifier of the authorization request.
+type OIDCLogin struct {
+	ID           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	StateHash    string             `bson:"state_hash" json:"-"`
+	Nonce        string             `bson:"nonce" json:"-"`
+	CodeVerifier string             `bson:"code_verifier" json:"-"`
+	CreatedAt    time.Time          `bson:"created_at" json:"created_at"`
+	ExpiresAt    time.Time          `bson:"expires_at" json:"expires_at"`
+}
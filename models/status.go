@@ -11,6 +11,7 @@ type Status struct {
 	ProjectID primitive.ObjectID   `bson:"project_id" json:"project_id"`
 	CreatorID primitive.ObjectID   `bson:"creator_id" json:"-"`
 	Name      string               `bson:"name" json:"name"`
+	Position  int                  `bson:"position" json:"position"`
 	IssueIDs  []primitive.ObjectID `bson:"issues" json:"issues_id"`
 	CreatedAt time.Time            `bson:"created_at" json:"created_at"`
 	UpdatedAt time.Time            `bson:"updated_at,omitempty" json:"updated_at,omitempty"`
@@ -1,20 +1,47 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type User struct {
-	ID                primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
-	FullName          string               `bson:"full_name" json:"full_name"`
-	Email             string               `bson:"email" json:"email"`
-	Password          string               `bson:"password" json:"password"`
-	AssignedIssues    []primitive.ObjectID `bson:"assigned_issues,omitempty" json:"-"`
-	ProjectSize       int                  `bson:"project_size" json:"project_size"`
-	Subscriptions     []primitive.ObjectID `bson:"subscriptions,omitempty" json:"-"`
-	OwnedProjects     []primitive.ObjectID `bson:"owned_projects,omitempty" json:"-"`
-	TeamProjects      []primitive.ObjectID `bson:"team_projects,omitempty" json:"-"`
-	IsAdmin           bool                 `bson:"is_admin" json:"is_admin"`
-	VerificationToken string               `bson:"verificationtoken,omitempty" json:"verificationtoken,omitempty"`
-	IsVerified        bool                 `bson:"isverified" json:"isverified"`
+	ID             primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	FullName       string               `bson:"full_name" json:"full_name"`
+	Email          string               `bson:"email" json:"email"`
+	Password       string               `bson:"password" json:"password"`
+	AssignedIssues []primitive.ObjectID `bson:"assigned_issues,omitempty" json:"-"`
+	ProjectSize    int                  `bson:"project_size" json:"project_size"`
+	Subscriptions  []primitive.ObjectID `bson:"subscriptions,omitempty" json:"-"`
+	OwnedProjects  []primitive.ObjectID `bson:"owned_projects,omitempty" json:"-"`
+	TeamProjects   []primitive.ObjectID `bson:"team_projects,omitempty" json:"-"`
+	IsAdmin        bool                 `bson:"is_admin" json:"is_admin"`
+	// VerificationToken stores the hash of the emailed verification token.
+	VerificationToken     string     `bson:"verificationtoken,omitempty" json:"-"`
+	VerificationExpiresAt *time.Time `bson:"verification_expires_at,omitempty" json:"-"`
+	VerificationSentAt    *time.Time `bson:"verification_sent_at,omitempty" json:"-"`
+	IsVerified            bool       `bson:"isverified" json:"isverified"`
+	// PasswordResetSentAt limits how often reset links are emailed.
+	PasswordResetSentAt *time.Time `bson:"password_reset_sent_at,omitempty" json:"-"`
+	// PendingEmail holds a requested new address until it is confirmed
+	// through the token whose hash is EmailChangeToken.
+	PendingEmail         string     `bson:"pending_email,omitempty" json:"pending_email,omitempty"`
+	EmailChangeToken     string     `bson:"email_change_token,omitempty" json:"-"`
+	EmailChangeExpiresAt *time.Time `bson:"email_change_expires_at,omitempty" json:"-"`
+	// TwoFactorSecret is the TOTP secret of an enabled second login step;
+	// TwoFactorPendingSecret holds a new secret until its first code is
+	// verified. RecoveryCodes stores hashes of the unused recovery codes.
+	TwoFactorEnabled       bool     `bson:"two_factor_enabled" json:"two_factor_enabled"`
+	TwoFactorSecret        string   `bson:"two_factor_secret,omitempty" json:"-"`
+	TwoFactorPendingSecret string   `bson:"two_factor_pending_secret,omitempty" json:"-"`
+	TwoFactorLastStep      int64    `bson:"two_factor_last_step,omitempty" json:"-"`
+	RecoveryCodes          []string `bson:"recovery_codes,omitempty" json:"-"`
+	// OIDCIssuer and OIDCSubject link the account to an identity provider
+	// account used for single sign-on.
+	OIDCIssuer        string                  `bson:"oidc_issuer,omitempty" json:"-"`
+	OIDCSubject       string                  `bson:"oidc_subject,omitempty" json:"-"`
+	NotificationPrefs NotificationPreferences `bson:"notification_prefs,omitempty" json:"notification_prefs"`
+	// TokensValidAfter invalidates every access token issued before it.
+	TokensValidAfter *time.Time `bson:"tokens_valid_after,omitempty" json:"-"`
 }
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"slices"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook event types. Board events share their names with the realtime
+// stream; invite events are only delivered to webhooks.
+const (
+	WebhookIssueCreated   = "issue.created"
+	WebhookIssueUpdated   = "issue.updated"
+	WebhookIssueMoved     = "issue.moved"
+	WebhookIssueDeleted   = "issue.deleted"
+	WebhookStatusCreated  = "status.created"
+	WebhookStatusDeleted  = "status.deleted"
+	WebhookMemberJoined   = "member.joined"
+	WebhookInviteSent     = "invite.sent"
+	WebhookInviteAccepted = "invite.accepted"
+	WebhookInviteDeclined = "invite.declined"
+)
+
+var WebhookEvents = []string{
+	WebhookIssueCreated, WebhookIssueUpdated, WebhookIssueMoved, WebhookIssueDeleted,
+	WebhookStatusCreated, WebhookStatusDeleted,
+	WebhookMemberJoined,
+	WebhookInviteSent, WebhookInviteAccepted, WebhookInviteDeclined,
+}
+
+// Webhook delivers the project's events to URL. An empty Events list
+// subscribes to every event.
+type Webhook struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID primitive.ObjectID `bson:"project_id" json:"project_id"`
+	URL       string             `bson:"url" json:"url"`
+	Secret    string             `bson:"secret" json:"-"`
+	Events    []string           `bson:"events" json:"events"`
+	Active    bool               `bson:"active" json:"active"`
+	CreatedBy primitive.ObjectID `bson:"created_by" json:"created_by"`
+	CreatedAt time.Time          `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time          `bson:"updated_at" json:"updated_at"`
+}
+
+// Subscribed reports whether the webhook wants the event.
+func (w *Webhook) Subscribed(event string) bool {
+	return len(w.Events) == 0 || slices.Contains(w.Events, event)
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySending   DeliveryStatus = "sending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookAttempt records one HTTP request of a delivery.
+type WebhookAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Response   string    `bson:"response,omitempty" json:"response,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"`
+}
+
+// WebhookDelivery is one event for one webhook. Payload is the exact body
+// sent, so redeliveries are byte for byte identical.
+type WebhookDelivery struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WebhookID     primitive.ObjectID `bson:"webhook_id" json:"webhook_id"`
+	ProjectID     primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Event         string             `bson:"event" json:"event"`
+	Payload       string             `bson:"payload" json:"payload"`
+	Status        DeliveryStatus     `bson:"status" json:"status"`
+	Attempts      int                `bson:"attempts" json:"attempts"`
+	History       []WebhookAttempt   `bson:"history,omitempty" json:"history"`
+	NextAttemptAt time.Time          `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   *time.Time         `bson:"locked_until,omitempty" json:"-"`
+	CreatedAt     time.Time          `bson:"created_at" json:"created_at"`
+	DeliveredAt   *time.Time         `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+}
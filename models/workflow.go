@@ -0,0 +1,37 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Fields that a workflow transition can require.
+const (
+	FieldResolution  = "resolution"
+	FieldAssignee    = "assignee"
+	FieldDueDate     = "due_date"
+	FieldDescription = "description"
+)
+
+var TransitionFields = []string{FieldResolution, FieldAssignee, FieldDueDate, FieldDescription}
+
+// WorkflowTransition allows issues to move from one status to another. A zero
+// FromStatusID matches issues in any status. An empty AllowedRoles lets every
+// role that can update issues make the move.
+type WorkflowTransition struct {
+	FromStatusID   primitive.ObjectID `bson:"from_status_id,omitempty" json:"from_status_id,omitempty"`
+	ToStatusID     primitive.ObjectID `bson:"to_status_id" json:"to_status_id"`
+	RequiredFields []string           `bson:"required_fields,omitempty" json:"required_fields,omitempty"`
+	AllowedRoles   []string           `bson:"allowed_roles,omitempty" json:"allowed_roles,omitempty"`
+}
+
+// Workflow holds the transition rules of a project. A project without
+// transitions allows every move.
+type Workflow struct {
+	ID          primitive.ObjectID   `bson:"_id,omitempty" json:"id"`
+	ProjectID   primitive.ObjectID   `bson:"project_id" json:"project_id"`
+	Transitions []WorkflowTransition `bson:"transitions" json:"transitions"`
+	UpdatedBy   primitive.ObjectID   `bson:"updated_by" json:"updated_by"`
+	UpdatedAt   time.Time            `bson:"updated_at" json:"updated_at"`
+}